@@ -765,3 +765,82 @@ func BenchmarkToSliceSafe(b *testing.B) {
 func BenchmarkToSliceUnsafe(b *testing.B) {
 	benchToSlice(b, NewUnsafeSet())
 }
+
+func benchUnionWith(b *testing.B, n int, s, t Set) {
+	nums := nrand(int(float64(n) * float64(1.5)))
+	for _, v := range nums[:n] {
+		s.Add(v)
+	}
+	for _, v := range nums[n/2:] {
+		t.Add(v)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.UnionWith(t)
+	}
+}
+
+func BenchmarkUnionWith1Safe(b *testing.B) {
+	benchUnionWith(b, 1, NewSet(), NewSet())
+}
+
+func BenchmarkUnionWith1Unsafe(b *testing.B) {
+	benchUnionWith(b, 1, NewUnsafeSet(), NewUnsafeSet())
+}
+
+func BenchmarkUnionWith10Safe(b *testing.B) {
+	benchUnionWith(b, 10, NewSet(), NewSet())
+}
+
+func BenchmarkUnionWith10Unsafe(b *testing.B) {
+	benchUnionWith(b, 10, NewUnsafeSet(), NewUnsafeSet())
+}
+
+func BenchmarkUnionWith100Safe(b *testing.B) {
+	benchUnionWith(b, 100, NewSet(), NewSet())
+}
+
+func BenchmarkUnionWith100Unsafe(b *testing.B) {
+	benchUnionWith(b, 100, NewUnsafeSet(), NewUnsafeSet())
+}
+
+func benchDenseUnionWith(b *testing.B, n int, s, t Set) {
+	for _, v := range rand.Perm(n * 2)[:n] {
+		s.Add(v)
+	}
+	for _, v := range rand.Perm(n * 2)[:n] {
+		t.Add(v)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.UnionWith(t)
+	}
+}
+
+func BenchmarkDenseUnionWith100000Safe(b *testing.B) {
+	benchDenseUnionWith(b, 100000, NewSet(), NewSet())
+}
+
+func BenchmarkDenseUnionWith100000Int(b *testing.B) {
+	benchDenseUnionWith(b, 100000, NewIntSet(), NewIntSet())
+}
+
+func benchContainsTuple(b *testing.B, s Set) {
+	tuple := NewTuple(1, "a", 2.5)
+	s.Add(tuple)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Contains(tuple)
+	}
+}
+
+func BenchmarkContainsTupleCached(b *testing.B) {
+	benchContainsTuple(b, NewUnsafeSet())
+}
+
+func BenchmarkContainsTupleUncached(b *testing.B) {
+	benchContainsTuple(b, SetOptions{Unsafe: true}.New())
+}
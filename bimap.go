@@ -0,0 +1,161 @@
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBiMapConflict is returned if a key or value of a BiMap is already associated with another value or key.
+var ErrBiMapConflict = errors.New("bimap key or value is already mapped")
+
+// BiMap is a one-to-one map whose keys and values are both hashed like set elements,
+// so that values can be looked up by keys and keys by values.
+// Operations on a BiMap are thread-safe.
+type BiMap struct {
+	mu       *sync.RWMutex
+	forward  *threadUnsafeMap
+	backward *threadUnsafeMap
+}
+
+// NewBiMap creates an empty bidirectional map.
+func NewBiMap() *BiMap {
+	options := SetOptions{Cache: true, Unsafe: true}
+	return &BiMap{
+		mu:       &sync.RWMutex{},
+		forward:  options.NewHashMap().(*threadUnsafeMap),
+		backward: options.NewHashMap().(*threadUnsafeMap),
+	}
+}
+
+// BiMapOf creates a bidirectional map of the pairs of the given set.
+// It returns ErrBiMapConflict if two pairs share a key or a value, and an error if the set contains other elements.
+func BiMapOf(pairs Set) (*BiMap, error) {
+	m := NewBiMap()
+	var err error
+	pairs.Each(func(elem interface{}) bool {
+		pair, ok := elem.(OrderedPair)
+		if !ok {
+			err = fmt.Errorf("bimap requires OrderedPair elements, got %v of type %T", elem, elem)
+			return true
+		}
+		err = m.Put(pair.First, pair.Second)
+		return err != nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Put associates the given key and value with each other.
+// It returns ErrBiMapConflict if the key is associated with another value or the value with another key.
+func (m *BiMap) Put(key, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if owner, ok := m.backward.Get(value); ok {
+		// putting an existing pair again is no conflict
+		if m.forward.keys.hashFor(owner) == m.forward.keys.hashFor(key) {
+			return nil
+		}
+		return ErrBiMapConflict
+	}
+	if m.forward.ContainsKey(key) {
+		return ErrBiMapConflict
+	}
+	m.forward.Put(key, value)
+	m.backward.Put(value, key)
+	return nil
+}
+
+// ForcePut associates the given key and value with each other and removes their previous associations.
+func (m *BiMap) ForcePut(key, value interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if previous, ok := m.forward.Get(key); ok {
+		m.backward.Delete(previous)
+	}
+	if previous, ok := m.backward.Get(value); ok {
+		m.forward.Delete(previous)
+	}
+	m.forward.Put(key, value)
+	m.backward.Put(value, key)
+}
+
+// Value provides the value of the given key. If the key doesn't exist, ok is false.
+// Lookups lock the map for writing since hashing uses the hash caches and the hashers of the map.
+func (m *BiMap) Value(key interface{}) (value interface{}, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.forward.Get(key)
+}
+
+// Key provides the key of the given value. If the value doesn't exist, ok is false.
+func (m *BiMap) Key(value interface{}) (key interface{}, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.backward.Get(value)
+}
+
+// DeleteKey removes the given key and its value.
+func (m *BiMap) DeleteKey(key interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if value, ok := m.forward.Get(key); ok {
+		m.forward.Delete(key)
+		m.backward.Delete(value)
+	}
+}
+
+// DeleteValue removes the given value and its key.
+func (m *BiMap) DeleteValue(value interface{}) {
+	m.Inverse().DeleteKey(value)
+}
+
+// Len provides the number of pairs.
+func (m *BiMap) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.forward.Len()
+}
+
+// Inverse provides a view of the map with keys and values swapped.
+// Modifications of the view are reflected by this map and vice versa.
+func (m *BiMap) Inverse() *BiMap {
+	return &BiMap{
+		mu:       m.mu,
+		forward:  m.backward,
+		backward: m.forward,
+	}
+}
+
+// KeySet provides the set of all keys.
+func (m *BiMap) KeySet() Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.forward.Keys().ThreadSafe()
+}
+
+// ValueSet provides the set of all values.
+func (m *BiMap) ValueSet() Set {
+	return m.Inverse().KeySet()
+}
+
+// Pairs provides the set of all pairs of keys and values as OrderedPair values.
+func (m *BiMap) Pairs() Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pairs := NewSet()
+	m.forward.Each(func(key, value interface{}) bool {
+		pairs.Add(OrderedPair{First: key, Second: value})
+		return false
+	})
+	return pairs
+}
+
+// String provides the pairs in the form "BiMap{key: value, ...}".
+func (m *BiMap) String() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return "Bi" + m.forward.String()
+}
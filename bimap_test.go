@@ -0,0 +1,153 @@
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_BiMapPut(t *testing.T) {
+	m := NewBiMap()
+	if err := m.Put("a", []int{1}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := m.Put("a", []int{1}); err != nil {
+		t.Errorf("putting an existing pair should succeed, got %v", err)
+	}
+	if err := m.Put("a", []int{2}); err != ErrBiMapConflict {
+		t.Errorf("remapping a key should conflict, got %v", err)
+	}
+	if err := m.Put("b", []int{1}); err != ErrBiMapConflict {
+		t.Errorf("remapping a value should conflict, got %v", err)
+	}
+	if m.Len() != 1 {
+		t.Errorf("conflicts should not change the map, got %v", m)
+	}
+
+	if v, ok := m.Value("a"); !ok || !NewSet(v).Contains([]int{1}) {
+		t.Errorf("value should be found, got %v, %v", v, ok)
+	}
+	if k, ok := m.Key([]int{1}); !ok || k != "a" {
+		t.Errorf("key should be found by its deeply equal value, got %v, %v", k, ok)
+	}
+	if _, ok := m.Key([]int{2}); ok {
+		t.Error("missing value should not be found")
+	}
+}
+
+func Test_BiMapForcePut(t *testing.T) {
+	m := NewBiMap()
+	_ = m.Put("a", 1)
+	_ = m.Put("b", 2)
+
+	m.ForcePut("a", 2)
+	if m.Len() != 1 {
+		t.Fatalf("conflicting pairs should be removed, got %v", m)
+	}
+	if k, _ := m.Key(2); k != "a" {
+		t.Errorf("value should be mapped to the new key, got %v", k)
+	}
+	if _, ok := m.Value("b"); ok {
+		t.Error("previous key of the value should be removed")
+	}
+	if _, ok := m.Key(1); ok {
+		t.Error("previous value of the key should be removed")
+	}
+}
+
+func Test_BiMapDelete(t *testing.T) {
+	m := NewBiMap()
+	_ = m.Put("a", 1)
+	_ = m.Put("b", 2)
+
+	m.DeleteKey("a")
+	m.DeleteValue(2)
+	m.DeleteKey("missing")
+	if m.Len() != 0 || !m.ValueSet().Empty() {
+		t.Errorf("map should be empty, got %v", m)
+	}
+}
+
+func Test_BiMapInverse(t *testing.T) {
+	m := NewBiMap()
+	_ = m.Put("a", 1)
+	inverse := m.Inverse()
+
+	if k, ok := inverse.Value(1); !ok || k != "a" {
+		t.Errorf("inverse should map values to keys, got %v, %v", k, ok)
+	}
+	if err := inverse.Put(2, "b"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v, _ := m.Value("b"); v != 2 {
+		t.Errorf("inverse should share the pairs, got %v", v)
+	}
+	if !m.KeySet().Equal(NewSet("a", "b")) || !m.ValueSet().Equal(NewSet(1, 2)) {
+		t.Errorf("unexpected key and value sets of %v", m)
+	}
+	if !inverse.KeySet().Equal(m.ValueSet()) {
+		t.Errorf("inverse keys should be the values, got %v", inverse.KeySet())
+	}
+}
+
+func Test_BiMapPairs(t *testing.T) {
+	pairs := NewSet(OrderedPair{First: "a", Second: 1}, OrderedPair{First: "b", Second: 2})
+	m, err := BiMapOf(pairs)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !m.Pairs().Equal(pairs) {
+		t.Errorf("pairs should be preserved, got %v", m.Pairs())
+	}
+
+	pairs.Add(OrderedPair{First: "c", Second: 1})
+	if _, err := BiMapOf(pairs); err != ErrBiMapConflict {
+		t.Errorf("shared values should conflict, got %v", err)
+	}
+	if _, err := BiMapOf(NewSet("a")); err == nil {
+		t.Error("elements other than pairs should be rejected")
+	}
+}
+
+func Test_BiMapConcurrent(t *testing.T) {
+	m := NewBiMap()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.ForcePut(i, -i)
+			m.Inverse().Value(-i)
+			m.Pairs()
+		}(i)
+	}
+	wg.Wait()
+	if m.Len() != 100 {
+		t.Errorf("expected 100 pairs, got %d", m.Len())
+	}
+}
+
+func Test_BiMapConcurrentReaders(t *testing.T) {
+	m := NewBiMap()
+	for i := 0; i < 10; i++ {
+		m.ForcePut(i, -i)
+	}
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if value, ok := m.Value(i % 10); !ok || value != -(i%10) {
+					t.Errorf("expected value %d, got %v", -(i % 10), value)
+				}
+				m.Key(-i)
+				m.KeySet().Add(n, i)
+				m.ValueSet().Add(-n, -i)
+			}
+		}(n)
+	}
+	wg.Wait()
+	if m.Len() != 10 {
+		t.Errorf("expected 10 pairs, got %d", m.Len())
+	}
+}
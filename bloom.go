@@ -0,0 +1,272 @@
+package mapset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// bloomVersion is the version of the binary encoding of a BloomSet.
+const bloomVersion = 1
+
+// bloomSeed decorrelates the two hash functions that are derived from an element hash.
+const bloomSeed = 0xc2b2ae3d27d4eb4f
+
+// ErrBloomMismatch is returned if two bloom filters with different parameters are combined.
+var ErrBloomMismatch = errors.New("bloom filters have different parameters")
+
+// ErrBloomUninitialized is the panic value if a BloomSet is used that has no filter yet.
+var ErrBloomUninitialized = errors.New("bloom set is not initialized, create it with NewBloomSet or BloomOptions.New")
+
+// BloomOptions contain options that affect the construction of a BloomSet.
+type BloomOptions struct {
+	// SetOptions affect how the elements are hashed. Combined filters must use the same hash function.
+	// Since bloom sets are meant to be large, the hashing cache is best left disabled.
+	SetOptions
+	// Capacity is the expected number of elements.
+	Capacity uint
+	// FalsePositiveRate is the probability that Contains reports an absent element once Capacity elements were added.
+	// It must be between 0 and 1.
+	FalsePositiveRate float64
+	// Exact enables the hybrid mode that additionally stores the elements in an exact set.
+	// The filter gates the lookups so that only the elements that pass it are looked up in the exact set.
+	Exact bool
+}
+
+// BloomSet is a probabilistic set that only stores k bits per element.
+// Contains never reports false negatives, but it may report false positives unless the hybrid mode is enabled.
+// Operations on a BloomSet are thread-safe.
+// The zero value has no filter and can only be decoded by UnmarshalBinary,
+// other operations panic with ErrBloomUninitialized.
+type BloomSet struct {
+	sync.Mutex
+	m      uint64
+	k      uint64
+	bits   []uint64
+	exact  bool
+	hasher threadUnsafeSet
+}
+
+// NewBloomSet creates a bloom set that is sized for the given capacity and false positive rate,
+// and that contains the given elements.
+func NewBloomSet(capacity uint, falsePositiveRate float64, elements ...interface{}) *BloomSet {
+	options := BloomOptions{
+		Capacity:          capacity,
+		FalsePositiveRate: falsePositiveRate,
+	}
+	return options.New(elements...)
+}
+
+// New creates a new bloom set with the given options. It panics if the false positive rate is not between 0 and 1.
+func (o BloomOptions) New(elements ...interface{}) *BloomSet {
+	if o.FalsePositiveRate <= 0 || o.FalsePositiveRate >= 1 {
+		panic(fmt.Sprintf("bloom false positive rate %v is out of range (0, 1)", o.FalsePositiveRate))
+	}
+	capacity := math.Max(float64(o.Capacity), 1)
+	m := math.Ceil(-capacity * math.Log(o.FalsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(math.Round(m/capacity*math.Ln2), 1)
+
+	set := newBloomSet(uint64(m), uint64(k), o.SetOptions)
+	set.exact = o.Exact
+	set.Add(elements...)
+	return set
+}
+
+func newBloomSet(m, k uint64, options SetOptions) *BloomSet {
+	words := (m + 63) / 64
+	return &BloomSet{
+		m:      words * 64,
+		k:      k,
+		bits:   make([]uint64, words),
+		hasher: options.newThreadUnsafeSet(),
+	}
+}
+
+// Add the given elements to this set.
+func (set *BloomSet) Add(i ...interface{}) {
+	set.Lock()
+	defer set.Unlock()
+	set.checkInitialized()
+	for _, val := range i {
+		h := set.hasher.hashFor(val)
+		set.addHash(h)
+		if set.exact {
+			set.hasher.addWithHash(val, h)
+		}
+	}
+}
+
+// Contains determines whether the given items are all in the set.
+// Without the hybrid mode, the result may be a false positive.
+func (set *BloomSet) Contains(i ...interface{}) bool {
+	set.Lock()
+	defer set.Unlock()
+	set.checkInitialized()
+	for _, val := range i {
+		h := set.hasher.hashFor(val)
+		if !set.containsHash(h) {
+			return false
+		}
+		if set.exact && !set.hasher.containsHash(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cardinality determines the number of elements in the set.
+// Without the hybrid mode, it is estimated from the number of set bits.
+func (set *BloomSet) Cardinality() int {
+	set.Lock()
+	defer set.Unlock()
+	set.checkInitialized()
+	if set.exact {
+		return set.hasher.Cardinality()
+	}
+	var ones int
+	for _, word := range set.bits {
+		ones += bits.OnesCount64(word)
+	}
+	if uint64(ones) == set.m {
+		return math.MaxInt32
+	}
+	m := float64(set.m)
+	return int(math.Round(-m / float64(set.k) * math.Log(1-float64(ones)/m)))
+}
+
+// Exact provides a clone of the exact set of the hybrid mode, or nil if the hybrid mode is disabled.
+func (set *BloomSet) Exact() Set {
+	set.Lock()
+	defer set.Unlock()
+	if !set.exact {
+		return nil
+	}
+	return set.hasher.Clone()
+}
+
+// Parameters provides the number of bits m and the number of hash functions k of the filter.
+func (set *BloomSet) Parameters() (m, k uint64) {
+	return set.m, set.k
+}
+
+// Union provides a new bloom set with all elements in this set and the given set.
+// Both filters must have the same parameters, otherwise ErrBloomMismatch is returned.
+// The result is only hybrid if both sets are hybrid.
+func (set *BloomSet) Union(other *BloomSet) (*BloomSet, error) {
+	return set.combine(other, func(a, b uint64) uint64 { return a | b }, (*threadUnsafeSet).Union)
+}
+
+// Intersect provides a new bloom set with the elements that exist in both sets.
+// Both filters must have the same parameters, otherwise ErrBloomMismatch is returned.
+// The false positive rate of the intersected filter is at most the one of the larger operand.
+// The result is only hybrid if both sets are hybrid.
+func (set *BloomSet) Intersect(other *BloomSet) (*BloomSet, error) {
+	return set.combine(other, func(a, b uint64) uint64 { return a & b }, (*threadUnsafeSet).Intersect)
+}
+
+func (set *BloomSet) combine(
+	other *BloomSet,
+	op func(a, b uint64) uint64,
+	exactOp func(set *threadUnsafeSet, other Set) Set,
+) (*BloomSet, error) {
+	other.Lock()
+	m, k, otherBits := other.m, other.k, append([]uint64(nil), other.bits...)
+	var otherExact Set
+	if other.exact {
+		otherExact = other.hasher.Clone()
+	}
+	other.Unlock()
+
+	set.Lock()
+	defer set.Unlock()
+	if set.m != m || set.k != k {
+		return nil, ErrBloomMismatch
+	}
+	result := newBloomSet(set.m, set.k, set.hasher.options)
+	for n := range result.bits {
+		result.bits[n] = op(set.bits[n], otherBits[n])
+	}
+	if set.exact && otherExact != nil {
+		result.exact = true
+		// the result keeps its own hash cache instead of the one of this set
+		for h, elem := range exactOp(&set.hasher, otherExact).(*threadUnsafeSet).anyMap {
+			result.hasher.addWithHash(elem, h)
+		}
+	}
+	return result, nil
+}
+
+// MarshalBinary encodes the filter of the set. The elements of the hybrid mode are not encoded.
+func (set *BloomSet) MarshalBinary() ([]byte, error) {
+	set.Lock()
+	defer set.Unlock()
+	data := make([]byte, 17, 17+8*len(set.bits))
+	data[0] = bloomVersion
+	binary.BigEndian.PutUint64(data[1:], set.m)
+	binary.BigEndian.PutUint64(data[9:], set.k)
+	for _, word := range set.bits {
+		data = append(data, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(data[len(data)-8:], word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter that was encoded by MarshalBinary.
+// The decoded set keeps its hashing options, but it is not hybrid.
+func (set *BloomSet) UnmarshalBinary(data []byte) error {
+	if len(data) < 17 || data[0] != bloomVersion {
+		return errors.New("invalid bloom filter encoding")
+	}
+	m := binary.BigEndian.Uint64(data[1:])
+	k := binary.BigEndian.Uint64(data[9:])
+	words := data[17:]
+	if m == 0 || m%64 != 0 || k == 0 || uint64(len(words)) != m/8 {
+		return errors.New("invalid bloom filter encoding")
+	}
+
+	set.Lock()
+	defer set.Unlock()
+	set.m = m
+	set.k = k
+	set.exact = false
+	set.bits = make([]uint64, m/64)
+	for n := range set.bits {
+		set.bits[n] = binary.BigEndian.Uint64(words[8*n:])
+	}
+	set.hasher = set.hasher.options.newThreadUnsafeSet()
+	return nil
+}
+
+// checkInitialized panics with ErrBloomUninitialized if the set has no filter. It expects the set to be locked.
+func (set *BloomSet) checkInitialized() {
+	if set.m == 0 {
+		panic(ErrBloomUninitialized)
+	}
+}
+
+func (set *BloomSet) addHash(h uint64) {
+	h1, h2 := bloomHashes(h)
+	for i := uint64(0); i < set.k; i++ {
+		bit := (h1 + i*h2) % set.m
+		set.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (set *BloomSet) containsHash(h uint64) bool {
+	h1, h2 := bloomHashes(h)
+	for i := uint64(0); i < set.k; i++ {
+		bit := (h1 + i*h2) % set.m
+		if set.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes derives the two hash functions of the double hashing scheme from an element hash.
+func bloomHashes(h uint64) (h1, h2 uint64) {
+	return mix64(h), mix64(h^bloomSeed) | 1
+}
@@ -0,0 +1,120 @@
+package mapset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_BloomSetContains(t *testing.T) {
+	const size = 10000
+	set := NewBloomSet(size, 0.01)
+	for i := 0; i < size; i++ {
+		set.Add(fmt.Sprintf("https://example.com/%d", i))
+	}
+
+	for i := 0; i < size; i++ {
+		if !set.Contains(fmt.Sprintf("https://example.com/%d", i)) {
+			t.Fatalf("bloom set must not report false negatives, missing %d", i)
+		}
+	}
+
+	var falsePositives int
+	for i := size; i < 2*size; i++ {
+		if set.Contains(fmt.Sprintf("https://example.com/%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / size; rate > 0.02 {
+		t.Errorf("false positive rate %v exceeds the configured rate by far", rate)
+	}
+
+	if card := set.Cardinality(); card < size*95/100 || card > size*105/100 {
+		t.Errorf("estimated cardinality %d is too far from %d", card, size)
+	}
+}
+
+func Test_BloomSetHybrid(t *testing.T) {
+	options := BloomOptions{Capacity: 100, FalsePositiveRate: 0.5, Exact: true}
+	set := options.New(1, 2, 3)
+
+	for i := 4; i < 1000; i++ {
+		if set.Contains(i) {
+			t.Fatalf("hybrid bloom set must not report false positives, found %d", i)
+		}
+	}
+	if !set.Contains(1, 2, 3) {
+		t.Error("hybrid bloom set should contain its elements")
+	}
+	if set.Cardinality() != 3 {
+		t.Errorf("hybrid bloom set should count exactly, got %d", set.Cardinality())
+	}
+	assertEqual(set.Exact(), NewSet(1, 2, 3), t)
+}
+
+func Test_BloomSetUnionIntersect(t *testing.T) {
+	options := BloomOptions{Capacity: 100, FalsePositiveRate: 0.01, Exact: true}
+	a := options.New(1, 2, 3)
+	b := options.New(3, 4)
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !union.Contains(1, 2, 3, 4) {
+		t.Error("union should contain the elements of both sets")
+	}
+	assertEqual(union.Exact(), NewSet(1, 2, 3, 4), t)
+
+	intersection, err := a.Intersect(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !intersection.Contains(3) || intersection.Contains(1) || intersection.Contains(4) {
+		t.Error("hybrid intersection should only contain the common elements")
+	}
+
+	if _, err := a.Union(NewBloomSet(1000, 0.01)); err != ErrBloomMismatch {
+		t.Errorf("expected parameter mismatch error, got %v", err)
+	}
+
+	nonExact, err := a.Union(options.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonExact.Exact() == nil {
+		t.Error("union of hybrid sets should be hybrid")
+	}
+}
+
+func Test_BloomSetBinary(t *testing.T) {
+	set := NewBloomSet(100, 0.01, "one", "two")
+	data, err := set.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded BloomSet
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Contains("one", "two") {
+		t.Error("decoded bloom set should contain the encoded elements")
+	}
+	if m, k := decoded.Parameters(); m != set.m || k != set.k {
+		t.Errorf("decoded parameters (%d, %d) differ from (%d, %d)", m, k, set.m, set.k)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("truncated encoding should not be decoded")
+	}
+}
+
+func Test_BloomSetZeroValue(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrBloomUninitialized {
+			t.Errorf("zero bloom set should panic with ErrBloomUninitialized, got %v", r)
+		}
+	}()
+	var set BloomSet
+	set.Add("one")
+}
@@ -0,0 +1,276 @@
+package mapset
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// EvictionPolicy determines which element a bounded set evicts if it is full.
+type EvictionPolicy int
+
+const (
+	// LRUEviction evicts the least recently used element. Add and Contains count as use.
+	LRUEviction EvictionPolicy = iota
+	// LFUEviction evicts the least frequently used element. Add and Contains count as use.
+	// Elements that were used equally often are evicted in least recently used order.
+	LFUEviction
+	// FIFOEviction evicts the element that was added first, regardless of its use.
+	FIFOEviction
+)
+
+var evictionPolicyNames = [...]string{
+	LRUEviction:  "LRU",
+	LFUEviction:  "LFU",
+	FIFOEviction: "FIFO",
+}
+
+// String provides the name of the eviction policy.
+func (p EvictionPolicy) String() string {
+	if p < 0 || int(p) >= len(evictionPolicyNames) {
+		return fmt.Sprintf("EvictionPolicy(%d)", int(p))
+	}
+	return evictionPolicyNames[p]
+}
+
+// NewBoundedSet creates a set that never exceeds the given capacity and that contains the given elements.
+// If the set is full, adding a new element evicts another one according to the given policy.
+// Operations on the resulting set are thread-safe.
+func NewBoundedSet(capacity int, policy EvictionPolicy, elements ...interface{}) Set {
+	options := BoundedOptions{
+		SetOptions: SetOptions{Cache: true},
+		Capacity:   capacity,
+		Eviction:   policy,
+	}
+	return options.New(elements...)
+}
+
+// BoundedOptions contain options that affect the construction of a bounded set.
+// They are kept apart from SetOptions, since every set derived from a bounded set copies its SetOptions
+// but is unbounded, and since OnEvict would make SetOptions incomparable.
+type BoundedOptions struct {
+	// SetOptions affect the underlying set. Bounded sets are always thread-safe, the Unsafe option is ignored.
+	// Sets that are derived from a bounded set, e.g., by Union, are unbounded sets with these options.
+	SetOptions
+	// Capacity bounds the number of elements in the set. If the set is full, adding a new element evicts another one
+	// according to the Eviction policy.
+	Capacity int
+	// Eviction selects which element a bounded set evicts if it is full. The default is LRUEviction.
+	Eviction EvictionPolicy
+	// OnEvict is called with every element that a bounded set evicts to make room for a new one.
+	// It is called after the set was unlocked and may therefore access the set.
+	OnEvict func(elem interface{})
+}
+
+// New creates a new bounded set with the given options. It panics if the capacity is not positive.
+func (o BoundedOptions) New(elements ...interface{}) Set {
+	if o.Capacity < 1 {
+		panic(fmt.Sprintf("bounded set capacity must be positive, got %d", o.Capacity))
+	}
+	set := &boundedSet{
+		trackedSet: trackedSet{threadSafeSet: o.SetOptions.newThreadSafeSet()},
+		capacity:   o.Capacity,
+		policy:     o.Eviction,
+		onEvict:    o.OnEvict,
+		usages:     make(map[uint64]*usage),
+	}
+	set.tracker = set
+	set.queue.policy = set.policy
+	set.Add(elements...)
+	return set
+}
+
+type boundedSet struct {
+	trackedSet
+	capacity int
+	policy   EvictionPolicy
+	onEvict  func(elem interface{})
+	usages   map[uint64]*usage
+	queue    evictionQueue
+	// evicted collects the evicted elements until the set is unlocked
+	evicted []interface{}
+	// tick is a logical clock that orders the uses of the elements
+	tick uint64
+}
+
+// usage tracks the use of an element of a bounded set.
+type usage struct {
+	hash  uint64
+	count uint64
+	last  uint64
+	index int
+}
+
+// evictionQueue is a min-heap of usages whose first element is the next one to evict.
+type evictionQueue struct {
+	policy EvictionPolicy
+	items  []*usage
+}
+
+func (q *evictionQueue) Len() int { return len(q.items) }
+
+func (q *evictionQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.policy == LFUEviction && a.count != b.count {
+		return a.count < b.count
+	}
+	return a.last < b.last
+}
+
+func (q *evictionQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *evictionQueue) Push(x interface{}) {
+	u := x.(*usage)
+	u.index = len(q.items)
+	q.items = append(q.items, u)
+}
+
+func (q *evictionQueue) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+func (set *boundedSet) begin() {}
+
+// end calls the eviction callback with the evicted elements. It expects the set to be unlocked.
+func (set *boundedSet) end() {
+	set.Lock()
+	evicted := set.evicted
+	set.evicted = nil
+	set.Unlock()
+	if set.onEvict == nil {
+		return
+	}
+	for _, elem := range evicted {
+		set.onEvict(elem)
+	}
+}
+
+func (set *boundedSet) admit(map[uint64]interface{}) error {
+	return nil
+}
+
+func (set *boundedSet) untrack(h uint64) {
+	set.removeHash(h)
+}
+
+// track adds or uses an element and evicts other elements if the set is full.
+// The evicted elements are collected until the set is unlocked. It expects the set to be locked.
+func (set *boundedSet) track(val interface{}, h uint64) {
+	set.tick++
+	if u, ok := set.usages[h]; ok {
+		u.count++
+		if set.policy != FIFOEviction {
+			u.last = set.tick
+		}
+		heap.Fix(&set.queue, u.index)
+		return
+	}
+	for set.threadUnsafeSet.Cardinality() >= set.capacity {
+		victim := set.queue.items[0].hash
+		set.evicted = append(set.evicted, set.threadUnsafeSet.anyMap[victim])
+		set.removeHash(victim)
+	}
+	set.threadUnsafeSet.addWithHash(val, h)
+	u := &usage{hash: h, count: 1, last: set.tick}
+	heap.Push(&set.queue, u)
+	set.usages[h] = u
+}
+
+// removeHash removes an element and its usage. It expects the set to be locked.
+func (set *boundedSet) removeHash(h uint64) {
+	set.threadUnsafeSet.removeWithHash(h)
+	if u, ok := set.usages[h]; ok {
+		heap.Remove(&set.queue, u.index)
+		delete(set.usages, h)
+	}
+}
+
+// compact drops the usages of elements that were removed by the underlying set. It expects the set to be locked.
+func (set *boundedSet) compact() {
+	for h, u := range set.usages {
+		if !set.threadUnsafeSet.containsHash(h) {
+			heap.Remove(&set.queue, u.index)
+			delete(set.usages, h)
+		}
+	}
+}
+
+func (set *boundedSet) Contains(i ...interface{}) bool {
+	set.Lock()
+	defer set.Unlock()
+	contains := set.threadUnsafeSet.Contains(i...)
+	for _, val := range i {
+		h := set.hashFor(val)
+		if elem, ok := set.threadUnsafeSet.anyMap[h]; ok {
+			set.track(elem, h)
+		}
+	}
+	return contains
+}
+
+// Pop removes and returns the element that would be evicted next.
+func (set *boundedSet) Pop() interface{} {
+	set.Lock()
+	defer set.Unlock()
+	if len(set.queue.items) == 0 {
+		return nil
+	}
+	h := set.queue.items[0].hash
+	elem := set.threadUnsafeSet.anyMap[h]
+	set.removeHash(h)
+	return elem
+}
+
+func (set *boundedSet) UpdateHash() int {
+	set.Lock()
+	defer set.Unlock()
+	moved := make(map[uint64]*usage)
+	updated := set.threadUnsafeSet.updateHash(func(previous, current uint64) {
+		if u, ok := set.usages[previous]; ok {
+			delete(set.usages, previous)
+			if _, ok := moved[current]; ok {
+				heap.Remove(&set.queue, u.index)
+				return
+			}
+			moved[current] = u
+		}
+	})
+	for h, u := range moved {
+		if _, ok := set.usages[h]; ok {
+			// the element collided with another one and was dropped by the underlying set
+			heap.Remove(&set.queue, u.index)
+			continue
+		}
+		u.hash = h
+		set.usages[h] = u
+	}
+	set.compact()
+	return updated
+}
+
+func (set *boundedSet) cloneWith(core *threadUnsafeSet) Set {
+	clone := &boundedSet{
+		trackedSet: trackedSet{threadSafeSet: threadSafeSet{threadUnsafeSet: *core}},
+		capacity:   set.capacity,
+		policy:     set.policy,
+		onEvict:    set.onEvict,
+		usages:     make(map[uint64]*usage, len(set.usages)),
+		queue: evictionQueue{
+			policy: set.policy,
+			items:  make([]*usage, len(set.queue.items)),
+		},
+		tick: set.tick,
+	}
+	for n, u := range set.queue.items {
+		copied := *u
+		clone.queue.items[n] = &copied
+		clone.usages[u.hash] = &copied
+	}
+	clone.tracker = clone
+	return clone
+}
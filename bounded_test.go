@@ -0,0 +1,178 @@
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_BoundedSetEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  EvictionPolicy
+		use     func(set Set)
+		want    Set
+		evicted []interface{}
+	}{
+		{
+			name:   "lru",
+			policy: LRUEviction,
+			use: func(set Set) {
+				set.Contains(1)
+				set.Add(4)
+				set.Add(1, 5)
+			},
+			want:    NewSet(1, 4, 5),
+			evicted: []interface{}{2, 3},
+		},
+		{
+			name:   "lfu",
+			policy: LFUEviction,
+			use: func(set Set) {
+				set.Contains(1)
+				set.Contains(1)
+				set.Contains(3)
+				set.Add(4)
+				set.Add(5)
+			},
+			want:    NewSet(1, 3, 5),
+			evicted: []interface{}{2, 4},
+		},
+		{
+			name:   "fifo",
+			policy: FIFOEviction,
+			use: func(set Set) {
+				set.Contains(1)
+				set.Add(1)
+				set.Add(4)
+				set.Add(5)
+			},
+			want:    NewSet(3, 4, 5),
+			evicted: []interface{}{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evicted []interface{}
+			options := BoundedOptions{
+				SetOptions: SetOptions{Cache: true},
+				Capacity:   3,
+				Eviction:   tt.policy,
+				OnEvict: func(elem interface{}) {
+					evicted = append(evicted, elem)
+				},
+			}
+			set := options.New()
+			set.Add(1)
+			set.Add(2)
+			set.Add(3)
+			tt.use(set)
+
+			if !set.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, set)
+			}
+			if !NewSet(evicted...).Equal(NewSet(tt.evicted...)) || len(evicted) != len(tt.evicted) {
+				t.Errorf("expected evictions %v, got %v", tt.evicted, evicted)
+			}
+			if set.Hash() != tt.want.Hash() {
+				t.Error("hash should be consistent with the evictions")
+			}
+		})
+	}
+}
+
+func Test_BoundedSetPop(t *testing.T) {
+	set := NewBoundedSet(3, LRUEviction, 1)
+	set.Add(2)
+	set.Add(3)
+	set.Contains(1)
+
+	for _, want := range []interface{}{2, 3, 1} {
+		if got := set.Pop(); got != want {
+			t.Errorf("expected %v to be popped, got %v", want, got)
+		}
+	}
+	if set.Pop() != nil {
+		t.Error("empty set should pop nil")
+	}
+}
+
+func Test_BoundedSetOptions(t *testing.T) {
+	// set options must stay comparable so that they can be used as map keys
+	sets := map[SetOptions]int{{Cache: true}: 1}
+	if sets[SetOptions{Cache: true}] != 1 {
+		t.Error("set options should be comparable")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("bounded set without capacity should panic")
+		}
+	}()
+	BoundedOptions{}.New()
+}
+
+func Test_BoundedSetOperations(t *testing.T) {
+	set := NewBoundedSet(3, FIFOEviction, 1, 2)
+	other := NewSet(2, 3, 4, 5)
+
+	if !set.Union(other).Equal(NewSet(1, 2, 3, 4, 5)) {
+		t.Error("union with an ordinary set should not be bounded")
+	}
+	if !set.Intersect(other).Equal(NewSet(2)) || !other.Intersect(set).Equal(NewSet(2)) {
+		t.Error("intersection with an ordinary set should work in both directions")
+	}
+
+	set.UnionWith(other)
+	if set.Cardinality() != 3 || !set.IsSubset(NewSet(1, 2, 3, 4, 5)) || set.Contains(1) {
+		t.Errorf("in-place union should evict the oldest elements first, got %v", set)
+	}
+
+	set.IntersectWith(NewSet(3, 4, 5))
+	set.DifferenceWith(NewSet(5))
+	if set.Cardinality() > 2 || set.Contains(5) {
+		t.Errorf("in-place operations should keep the bound, got %v", set)
+	}
+	set.Add(6, 7, 8)
+	if !set.Equal(NewSet(6, 7, 8)) {
+		t.Errorf("removed elements should not be evicted again, got %v", set)
+	}
+
+	clone := set.Clone()
+	clone.Add(9)
+	if !clone.Equal(NewSet(7, 8, 9)) || !set.Equal(NewSet(6, 7, 8)) {
+		t.Errorf("clone should be bounded independently, got %v and %v", clone, set)
+	}
+}
+
+func Test_BoundedSetUpdateHash(t *testing.T) {
+	nested := NewSet("foo")
+	set := NewBoundedSet(2, LRUEviction, nested, "bar")
+
+	nested.Add("baz")
+	if updated := set.UpdateHash(); updated != 1 {
+		t.Fatalf("expected 1 updated hash, got %d", updated)
+	}
+	set.Add("qux")
+	if !set.Equal(NewSet("bar", "qux")) {
+		t.Errorf("updated element should still be evicted first, got %v", set)
+	}
+}
+
+func Test_BoundedSetConcurrent(t *testing.T) {
+	set := NewBoundedSet(10, LFUEviction)
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				set.Add(n*100 + i)
+				set.Contains(i)
+			}
+		}(n)
+	}
+	wg.Wait()
+	if set.Cardinality() != 10 {
+		t.Errorf("expected 10 elements, got %d", set.Cardinality())
+	}
+}
@@ -0,0 +1,374 @@
+// Command pyraset performs set operations on files of line-delimited text, JSON arrays, or CSV columns.
+//
+// Usage:
+//
+//	pyraset [flags] <command> <file>...
+//
+// The commands are:
+//
+//	union      elements in any of the files
+//	intersect  elements in all of the files
+//	diff       elements in the first file but in none of the others
+//	symdiff    elements in an odd number of the files
+//	card       number of elements in any of the files
+//	jaccard    Jaccard similarity of two files
+//	subset     whether the first file is a subset of the second one
+//	superset   whether the first file is a superset of the second one
+//	equal      whether two files contain the same elements
+//	eval       evaluates an expression over named files, e.g., eval "(a | b) - c" a=a.txt b=b.txt c=c.txt
+//
+// A file name of "-" reads from the standard input, which can only be given once. All elements are compared as strings,
+// and the resulting elements are written in ascending string order. This includes JSON numbers, which keep their notation,
+// so that 10 is written before 9, and JSON output writes them as strings, e.g., ["1"] for the input [1].
+//
+// The exit code is 0 on success or if a predicate holds, 1 if a predicate does not hold, and 2 on errors.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pyraset "github.com/gofunky/pyraset/v2"
+)
+
+const (
+	exitTrue  = 0
+	exitFalse = 1
+	exitError = 2
+)
+
+const (
+	formatLines = "lines"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+type config struct {
+	in     string
+	out    string
+	column int
+	header bool
+	stdin  io.Reader
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line with the given arguments and provides the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("pyraset", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg := config{stdin: stdin}
+	flags.StringVar(&cfg.in, "in", "", "input format: lines, json, or csv (default: by file extension, otherwise lines)")
+	flags.StringVar(&cfg.out, "out", formatLines, "output format: lines, json, or csv")
+	flags.IntVar(&cfg.column, "column", 1, "1-based column of CSV input")
+	flags.BoolVar(&cfg.header, "header", false, "skip the first row of CSV input")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: pyraset [flags] union|intersect|diff|symdiff|card|jaccard|subset|superset|equal <file>...")
+		fmt.Fprintln(stderr, "       pyraset [flags] eval <expression> <name>=<file>...")
+		fmt.Fprintln(stderr, "Elements, including JSON numbers, are compared as strings and written in ascending string order.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return exitError
+	}
+	if err := checkFormat(cfg.out); err != nil {
+		fmt.Fprintln(stderr, "pyraset:", err)
+		return exitError
+	}
+
+	code, err := execute(cfg, flags.Arg(0), flags.Args()[1:], stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, "pyraset:", err)
+		return exitError
+	}
+	return code
+}
+
+func execute(cfg config, command string, args []string, stdout io.Writer) (int, error) {
+	if command == "eval" {
+		return eval(cfg, args, stdout)
+	}
+
+	min, max := 1, -1
+	switch command {
+	case "union", "intersect", "diff", "symdiff", "card":
+	case "jaccard", "subset", "superset", "equal":
+		min, max = 2, 2
+	default:
+		return exitError, fmt.Errorf("unknown command %q", command)
+	}
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return exitError, fmt.Errorf("%s requires %s", command, describeArity(min, max))
+	}
+	sets, err := loadAll(cfg, args)
+	if err != nil {
+		return exitError, err
+	}
+
+	switch command {
+	case "union":
+		return exitTrue, write(cfg.out, pyraset.UnionAll(sets...), stdout)
+	case "intersect":
+		return exitTrue, write(cfg.out, pyraset.IntersectAll(sets...), stdout)
+	case "diff":
+		return exitTrue, write(cfg.out, pyraset.DifferenceAll(sets[0], sets[1:]...), stdout)
+	case "symdiff":
+		result := sets[0]
+		for _, set := range sets[1:] {
+			result = result.SymmetricDifference(set)
+		}
+		return exitTrue, write(cfg.out, result, stdout)
+	case "card":
+		_, err := fmt.Fprintln(stdout, pyraset.UnionAll(sets...).Cardinality())
+		return exitTrue, err
+	case "jaccard":
+		_, err := fmt.Fprintf(stdout, "%g\n", sets[0].Jaccard(sets[1]))
+		return exitTrue, err
+	case "subset":
+		return predicate(sets[0].IsSubset(sets[1]), stdout)
+	case "superset":
+		return predicate(sets[0].IsSuperset(sets[1]), stdout)
+	default:
+		return predicate(sets[0].Equal(sets[1]), stdout)
+	}
+}
+
+func eval(cfg config, args []string, stdout io.Writer) (int, error) {
+	if len(args) < 1 {
+		return exitError, errors.New("eval requires an expression")
+	}
+	names := make([]string, 0, len(args)-1)
+	files := make([]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return exitError, fmt.Errorf("invalid set definition %q, expected <name>=<file>", arg)
+		}
+		names = append(names, parts[0])
+		files = append(files, parts[1])
+	}
+	sets, err := loadAll(cfg, files)
+	if err != nil {
+		return exitError, err
+	}
+	env := make(map[string]pyraset.Set, len(names))
+	for n, name := range names {
+		env[name] = sets[n]
+	}
+
+	result, err := pyraset.Eval(args[0], env)
+	if err != nil {
+		return exitError, err
+	}
+	if b, ok := result.(bool); ok {
+		return predicate(b, stdout)
+	}
+	return exitTrue, write(cfg.out, result.(pyraset.Set), stdout)
+}
+
+func predicate(holds bool, stdout io.Writer) (int, error) {
+	if _, err := fmt.Fprintln(stdout, holds); err != nil {
+		return exitError, err
+	}
+	if holds {
+		return exitTrue, nil
+	}
+	return exitFalse, nil
+}
+
+func describeArity(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("exactly %d files", min)
+	}
+	return fmt.Sprintf("at least %d file", min)
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case formatLines, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// loadAll reads the sets of the given files.
+// The standard input can only be read once, so that it must not be given more than once.
+func loadAll(cfg config, names []string) ([]pyraset.Set, error) {
+	stdin := false
+	sets := make([]pyraset.Set, len(names))
+	for n, name := range names {
+		if name == "-" {
+			if stdin {
+				return nil, errors.New(`the standard input "-" can only be given once`)
+			}
+			stdin = true
+		}
+		set, err := load(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		sets[n] = set
+	}
+	return sets, nil
+}
+
+// load reads the set of the given file.
+func load(cfg config, name string) (pyraset.Set, error) {
+	format := cfg.in
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json":
+			format = formatJSON
+		case ".csv":
+			format = formatCSV
+		default:
+			format = formatLines
+		}
+	}
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+
+	r := cfg.stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var elements []string
+	var err error
+	switch format {
+	case formatJSON:
+		elements, err = readJSON(r)
+	case formatCSV:
+		elements, err = readCSV(r, cfg.column, cfg.header)
+	default:
+		elements, err = readLines(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	set := pyraset.NewUnsafeSet()
+	for _, elem := range elements {
+		set.Add(elem)
+	}
+	return set, nil
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var elements []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			elements = append(elements, line)
+		}
+	}
+	return elements, scanner.Err()
+}
+
+func readJSON(r io.Reader) ([]string, error) {
+	var values []interface{}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if err := d.Decode(&values); err != nil {
+		return nil, err
+	}
+	elements := make([]string, 0, len(values))
+	for n, value := range values {
+		switch v := value.(type) {
+		case []interface{}, map[string]interface{}:
+			return nil, fmt.Errorf("element %d is not a primitive value", n)
+		case nil:
+			elements = append(elements, "null")
+		default:
+			elements = append(elements, fmt.Sprint(v))
+		}
+	}
+	return elements, nil
+}
+
+func readCSV(r io.Reader, column int, header bool) ([]string, error) {
+	if column < 1 {
+		return nil, fmt.Errorf("invalid column %d", column)
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	var elements []string
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return elements, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header && row == 1 {
+			continue
+		}
+		if column > len(record) {
+			return nil, fmt.Errorf("row %d has no column %d", row, column)
+		}
+		if field := record[column-1]; field != "" {
+			elements = append(elements, field)
+		}
+	}
+}
+
+// write outputs the elements of the set in ascending order.
+func write(format string, set pyraset.Set, w io.Writer) error {
+	elements := make([]string, 0, set.Cardinality())
+	set.Each(func(elem interface{}) bool {
+		elements = append(elements, fmt.Sprint(elem))
+		return false
+	})
+	sort.Strings(elements)
+
+	switch format {
+	case formatJSON:
+		b, err := json.Marshal(elements)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		for _, elem := range elements {
+			if err := writer.Write([]string{elem}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		buffered := bufio.NewWriter(w)
+		for _, elem := range elements {
+			if _, err := fmt.Fprintln(buffered, elem); err != nil {
+				return err
+			}
+		}
+		return buffered.Flush()
+	}
+}
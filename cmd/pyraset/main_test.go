@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "pyraset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func Test_run(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.txt":  "3\n1\n2\n\n2\n",
+		"b.json": `["2", 3, 4]`,
+		"c.csv":  "id,name\n4,four\n5,five\n",
+		"d.txt":  "1\r\n2\r\n",
+	})
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.json")
+	c := filepath.Join(dir, "c.csv")
+	d := filepath.Join(dir, "d.txt")
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+		code  int
+	}{
+		{name: "union", args: []string{"-header", "union", a, b, c}, want: "1\n2\n3\n4\n5\n"},
+		{name: "intersect", args: []string{"intersect", a, b}, want: "2\n3\n"},
+		{name: "diff", args: []string{"diff", a, b, d}, want: ""},
+		{name: "symdiff", args: []string{"symdiff", a, b}, want: "1\n4\n"},
+		{name: "card", args: []string{"card", a, b}, want: "4\n"},
+		{name: "jaccard", args: []string{"jaccard", a, b}, want: "0.5\n"},
+		{name: "subset", args: []string{"subset", d, a}, want: "true\n", code: exitTrue},
+		{name: "not subset", args: []string{"subset", a, d}, want: "false\n", code: exitFalse},
+		{name: "superset", args: []string{"superset", a, d}, want: "true\n", code: exitTrue},
+		{name: "equal", args: []string{"equal", a, b}, want: "false\n", code: exitFalse},
+		{name: "json output", args: []string{"-out", "json", "intersect", a, b}, want: "[\"2\",\"3\"]\n"},
+		{name: "csv output", args: []string{"-out", "csv", "diff", a, b}, want: "1\n"},
+		{name: "csv column", args: []string{"-header", "-column", "2", "union", c}, want: "five\nfour\n"},
+		{name: "forced format", args: []string{"-in", "lines", "card", b}, want: "1\n"},
+		{name: "stdin", args: []string{"union", "-", d}, stdin: "x\n", want: "1\n2\nx\n"},
+		{name: "stdin twice", args: []string{"union", "-", "-"}, stdin: "x\n", code: exitError},
+		{name: "eval stdin twice", args: []string{"eval", "a | b", "a=-", "b=-"}, stdin: "x\n", code: exitError},
+		{name: "symdiff of three", args: []string{"symdiff", a, b, d}, want: "2\n4\n"},
+		{
+			name: "eval set",
+			args: []string{"-header", "eval", "(a | c) - b", "a=" + a, "b=" + b, "c=" + c},
+			want: "1\n5\n",
+		},
+		{name: "eval predicate", args: []string{"eval", "d < a", "a=" + a, "d=" + d}, want: "true\n"},
+		{name: "unknown command", args: []string{"merge", a}, code: exitError},
+		{name: "missing file", args: []string{"union", filepath.Join(dir, "missing.txt")}, code: exitError},
+		{name: "wrong arity", args: []string{"jaccard", a}, code: exitError},
+		{name: "unknown format", args: []string{"-out", "xml", "union", a}, code: exitError},
+		{name: "no command", args: []string{}, code: exitError},
+		{name: "eval syntax error", args: []string{"eval", "a |", "a=" + a}, code: exitError},
+		{name: "csv missing column", args: []string{"-column", "3", "union", c}, code: exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("run() = %d, want %d, stderr: %s", code, tt.code, stderr.String())
+			}
+			if tt.code != exitError && stdout.String() != tt.want {
+				t.Errorf("run() printed %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
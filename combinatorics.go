@@ -0,0 +1,233 @@
+package mapset
+
+import (
+	"math/big"
+)
+
+// PowerSetCardinality determines the number of subsets of the given set, i.e., 2^n for n elements.
+func PowerSetCardinality(set Set) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(set.Cardinality()))
+}
+
+// SubsetsCardinality determines the number of subsets of the given set with k elements, i.e., n choose k.
+func SubsetsCardinality(set Set, k int) *big.Int {
+	n := set.Cardinality()
+	if k < 0 || k > n {
+		return new(big.Int)
+	}
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// LazyPowerSet enumerates all subsets of the given set without materializing the power set.
+// The subsets are produced by ascending cardinality. If filter is not nil, only the subsets it accepts are produced.
+// Stop the returned iterator to end the enumeration early.
+// The subsets are thread-safe unless the given set was created with the Unsafe option.
+func LazyPowerSet(set Set, filter func(Set) bool) *Iterator {
+	return newCombinator(set).iterate(func(c *combinator, emit func(Set) bool) {
+		for k := 0; k <= len(c.hashes); k++ {
+			if !c.combinations(k, emit) {
+				return
+			}
+		}
+	}, filter)
+}
+
+// Subsets enumerates all subsets of the given set with k elements without materializing them in advance.
+// If filter is not nil, only the subsets it accepts are produced.
+// Stop the returned iterator to end the enumeration early.
+// The subsets are thread-safe unless the given set was created with the Unsafe option.
+func Subsets(set Set, k int, filter func(Set) bool) *Iterator {
+	return newCombinator(set).iterate(func(c *combinator, emit func(Set) bool) {
+		c.combinations(k, emit)
+	}, filter)
+}
+
+// Permutations enumerates all orderings of the elements of the given set as slices.
+// Every produced slice is a new copy. Stop the returned iterator to end the enumeration early.
+func Permutations(set Set) *Iterator {
+	elements := set.ToSlice()
+	iterator, ch, stopCh := newIterator()
+
+	go func() {
+		defer close(ch)
+		emit := func() bool {
+			select {
+			case <-stopCh:
+				return false
+			case ch <- append([]interface{}(nil), elements...):
+				return true
+			}
+		}
+		if !emit() {
+			return
+		}
+		// Heap's algorithm, iteratively
+		counters := make([]int, len(elements))
+		for i := 1; i < len(elements); {
+			if counters[i] < i {
+				if i%2 == 0 {
+					elements[0], elements[i] = elements[i], elements[0]
+				} else {
+					elements[counters[i]], elements[i] = elements[i], elements[counters[i]]
+				}
+				if !emit() {
+					return
+				}
+				counters[i]++
+				i = 1
+			} else {
+				counters[i] = 0
+				i++
+			}
+		}
+	}()
+
+	return iterator
+}
+
+// combinator holds a snapshot of the elements of a set to enumerate its subsets.
+type combinator struct {
+	template *threadUnsafeSet
+	hashes   []uint64
+	elements []interface{}
+}
+
+func newCombinator(set Set) *combinator {
+	o := set.ThreadSafe()
+	o.RLock()
+	defer o.RUnlock()
+
+	c := &combinator{
+		template: o.threadUnsafeSet.derived(0),
+		hashes:   make([]uint64, 0, o.threadUnsafeSet.Cardinality()),
+		elements: make([]interface{}, 0, o.threadUnsafeSet.Cardinality()),
+	}
+	for h, elem := range o.threadUnsafeSet.anyMap {
+		c.hashes = append(c.hashes, h)
+		c.elements = append(c.elements, elem)
+	}
+	return c
+}
+
+// iterate runs the given enumeration in the background and sends every subset that passes the filter.
+func (c *combinator) iterate(enumerate func(c *combinator, emit func(Set) bool), filter func(Set) bool) *Iterator {
+	iterator, ch, stopCh := newIterator()
+
+	go func() {
+		defer close(ch)
+		enumerate(c, func(subset Set) bool {
+			select {
+			case <-stopCh:
+				return false
+			default:
+			}
+			if filter != nil && !filter(subset) {
+				return true
+			}
+			select {
+			case <-stopCh:
+				return false
+			case ch <- subset:
+				return true
+			}
+		})
+	}()
+
+	return iterator
+}
+
+// combinations emits all subsets with k elements in lexicographic order of their indices.
+// It returns false if the enumeration was stopped.
+func (c *combinator) combinations(k int, emit func(Set) bool) bool {
+	n := len(c.hashes)
+	if k < 0 || k > n {
+		return true
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	for {
+		if !emit(c.subset(indices)) {
+			return false
+		}
+		i := k - 1
+		for i >= 0 && indices[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return true
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+}
+
+func (c *combinator) subset(indices []int) Set {
+	subset := c.template.derived(len(indices))
+	for _, i := range indices {
+		subset.addWithHash(c.elements[i], c.hashes[i])
+	}
+	if c.template.options.Unsafe {
+		return subset
+	}
+	return subset.ThreadSafe()
+}
+
+// CartesianProductN builds the Cartesian product of the given sets as a set of Tuple values.
+// The product uses the same implementation and options as the first set.
+// The product of no sets is the set that only contains the empty tuple.
+func CartesianProductN(sets ...Set) Set {
+	if len(sets) == 0 {
+		return NewSet(NewTuple())
+	}
+	product := derivedFrom(sets[0], 0)
+	for tuple := range CartesianProductIterator(sets...).C {
+		product.Add(tuple)
+	}
+	return resultLike(sets[0], product)
+}
+
+// CartesianProductIterator enumerates the Cartesian product of the given sets as Tuple values
+// without materializing the product. Stop the returned iterator to end the enumeration early.
+func CartesianProductIterator(sets ...Set) *Iterator {
+	factors := make([][]interface{}, len(sets))
+	for n, set := range sets {
+		factors[n] = set.ToSlice()
+	}
+	iterator, ch, stopCh := newIterator()
+
+	go func() {
+		defer close(ch)
+		for _, factor := range factors {
+			if len(factor) == 0 {
+				return
+			}
+		}
+		indices := make([]int, len(factors))
+		for {
+			elements := make([]interface{}, len(factors))
+			for n, i := range indices {
+				elements[n] = factors[n][i]
+			}
+			select {
+			case <-stopCh:
+				return
+			case ch <- Tuple{elements: elements}:
+			}
+			n := len(indices) - 1
+			for n >= 0 && indices[n] == len(factors[n])-1 {
+				indices[n] = 0
+				n--
+			}
+			if n < 0 {
+				return
+			}
+			indices[n]++
+		}
+	}()
+
+	return iterator
+}
@@ -0,0 +1,132 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_LazyPowerSet(t *testing.T) {
+	a := NewSet(1, "delta", "chi", 4)
+
+	lazy := NewSet()
+	for subset := range LazyPowerSet(a, nil).C {
+		lazy.Add(subset)
+	}
+	assertEqual(lazy, a.PowerSet(), t)
+
+	if PowerSetCardinality(a).Int64() != 16 {
+		t.Errorf("unexpected power set cardinality %v", PowerSetCardinality(a))
+	}
+}
+
+func Test_LazyPowerSetFilter(t *testing.T) {
+	a := NewUnsafeSet(1, 2, 3, 4)
+
+	var count int
+	for subset := range LazyPowerSet(a, func(s Set) bool { return s.Contains(1) }).C {
+		if !subset.(Set).Contains(1) {
+			t.Errorf("filtered subset %v does not contain 1", subset)
+		}
+		count++
+	}
+	if count != 8 {
+		t.Errorf("expected 8 subsets containing 1, got %d", count)
+	}
+}
+
+func Test_LazyPowerSetStop(t *testing.T) {
+	a := NewSet()
+	for i := 0; i < 64; i++ {
+		a.Add(i)
+	}
+
+	it := LazyPowerSet(a, nil)
+	var count int
+	for subset := range it.C {
+		if _, ok := subset.(*threadSafeSet); !ok {
+			t.Error("subsets of a thread-safe set should be thread-safe")
+		}
+		count++
+		if count == 100 {
+			it.Stop()
+		}
+	}
+	if count != 100 {
+		t.Errorf("enumeration should end after stopping, got %d subsets", count)
+	}
+}
+
+func Test_Subsets(t *testing.T) {
+	a := NewSet(1, 2, 3, 4, 5)
+
+	for k := -1; k <= 6; k++ {
+		subsets := NewSet()
+		for subset := range Subsets(a, k, nil).C {
+			if subset.(Set).Cardinality() != k {
+				t.Errorf("subset %v does not have %d elements", subset, k)
+			}
+			subsets.Add(subset)
+		}
+		if int64(subsets.Cardinality()) != SubsetsCardinality(a, k).Int64() {
+			t.Errorf("expected %v subsets of %d elements, got %d", SubsetsCardinality(a, k), k, subsets.Cardinality())
+		}
+	}
+}
+
+func Test_Permutations(t *testing.T) {
+	a := NewSet("a", "b", "c", "d")
+
+	permutations := NewSet()
+	for permutation := range Permutations(a).C {
+		p := permutation.([]interface{})
+		if !a.Equal(NewSet(p...)) {
+			t.Errorf("permutation %v does not contain the elements of %v", p, a)
+		}
+		permutations.Add(p[0].(string) + p[1].(string) + p[2].(string) + p[3].(string))
+	}
+	if permutations.Cardinality() != 24 {
+		t.Errorf("expected 24 distinct permutations, got %d", permutations.Cardinality())
+	}
+}
+
+func Test_CartesianProductN(t *testing.T) {
+	a := NewSet(1, 2)
+	b := NewSet("a", "b", "c")
+	c := NewSet(true, false)
+
+	product := CartesianProductN(a, b, c)
+	if product.Cardinality() != 12 {
+		t.Errorf("unexpected cardinality %d of the cartesian product", product.Cardinality())
+	}
+	if !product.Contains(NewTuple(1, "c", false)) {
+		t.Error("cartesian product should contain the tuple (1, c, false)")
+	}
+	if _, ok := product.(*threadSafeSet); !ok {
+		t.Error("cartesian product of thread-safe sets should be thread-safe")
+	}
+
+	assertEqual(CartesianProductN(a, b), a.CartesianProduct(b), t)
+	assertEqual(CartesianProductN(a, NewSet(), c), NewSet(), t)
+	assertEqual(CartesianProductN(), NewSet(NewTuple()), t)
+}
+
+func Test_CartesianProductIteratorStop(t *testing.T) {
+	a := NewSet()
+	for i := 0; i < 100; i++ {
+		a.Add(i)
+	}
+
+	it := CartesianProductIterator(a, a, a)
+	var count int
+	for tuple := range it.C {
+		if tuple.(Tuple).Len() != 3 {
+			t.Errorf("unexpected tuple %v", tuple)
+		}
+		count++
+		if count == 10 {
+			it.Stop()
+		}
+	}
+	if count != 10 {
+		t.Errorf("enumeration should end after stopping, got %d tuples", count)
+	}
+}
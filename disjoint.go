@@ -0,0 +1,160 @@
+package mapset
+
+import (
+	"sync"
+)
+
+// DisjointSets is a union-find structure that groups elements into disjoint sets, e.g., to find connected entities.
+// Elements are identified by their hashes like in a set, so that equal elements share the same disjoint set.
+// Operations on DisjointSets are thread-safe.
+type DisjointSets struct {
+	sync.Mutex
+	parent   map[uint64]uint64
+	rank     map[uint64]int
+	elements map[uint64]interface{}
+	count    int
+	hasher   threadUnsafeSet
+}
+
+// NewDisjointSets creates disjoint sets that contain every given element in its own set.
+func NewDisjointSets(elements ...interface{}) *DisjointSets {
+	options := SetOptions{Cache: true}
+	sets := &DisjointSets{
+		parent:   make(map[uint64]uint64),
+		rank:     make(map[uint64]int),
+		elements: make(map[uint64]interface{}),
+		hasher:   options.newThreadUnsafeSet(),
+	}
+	sets.MakeSet(elements...)
+	return sets
+}
+
+// makeSet adds the given element in its own set unless it already exists and provides its hash.
+func (sets *DisjointSets) makeSet(i interface{}) uint64 {
+	h := sets.hasher.hashFor(i)
+	if _, ok := sets.parent[h]; !ok {
+		sets.parent[h] = h
+		sets.elements[h] = i
+		sets.count++
+	}
+	return h
+}
+
+// find provides the hash of the representative of the given element hash and compresses the path to it.
+func (sets *DisjointSets) find(h uint64) uint64 {
+	root := h
+	for sets.parent[root] != root {
+		root = sets.parent[root]
+	}
+	for h != root {
+		next := sets.parent[h]
+		sets.parent[h] = root
+		h = next
+	}
+	return root
+}
+
+// MakeSet adds every given element in its own set. Elements that already exist remain in their sets.
+func (sets *DisjointSets) MakeSet(i ...interface{}) {
+	sets.Lock()
+	defer sets.Unlock()
+	for _, val := range i {
+		sets.makeSet(val)
+	}
+}
+
+// Union merges the sets of the given elements, which are added if they don't exist yet.
+// It reports whether the sets were disjoint before.
+func (sets *DisjointSets) Union(a, b interface{}) bool {
+	sets.Lock()
+	defer sets.Unlock()
+	x, y := sets.find(sets.makeSet(a)), sets.find(sets.makeSet(b))
+	if x == y {
+		return false
+	}
+	// union by rank keeps the trees shallow
+	if sets.rank[x] < sets.rank[y] {
+		x, y = y, x
+	}
+	sets.parent[y] = x
+	if sets.rank[x] == sets.rank[y] {
+		sets.rank[x]++
+	}
+	delete(sets.rank, y)
+	sets.count--
+	return true
+}
+
+// Find provides the representative element of the set of the given element.
+// If the element doesn't exist, ok is false.
+func (sets *DisjointSets) Find(i interface{}) (representative interface{}, ok bool) {
+	sets.Lock()
+	defer sets.Unlock()
+	h := sets.hasher.hashFor(i)
+	if _, ok := sets.parent[h]; !ok {
+		return nil, false
+	}
+	return sets.elements[sets.find(h)], true
+}
+
+// Connected determines whether both given elements exist and are in the same set.
+func (sets *DisjointSets) Connected(a, b interface{}) bool {
+	sets.Lock()
+	defer sets.Unlock()
+	x, y := sets.hasher.hashFor(a), sets.hasher.hashFor(b)
+	_, okX := sets.parent[x]
+	_, okY := sets.parent[y]
+	return okX && okY && sets.find(x) == sets.find(y)
+}
+
+// Len provides the number of elements.
+func (sets *DisjointSets) Len() int {
+	sets.Lock()
+	defer sets.Unlock()
+	return len(sets.parent)
+}
+
+// Count provides the number of disjoint sets.
+func (sets *DisjointSets) Count() int {
+	sets.Lock()
+	defer sets.Unlock()
+	return sets.count
+}
+
+// SetOf provides the set that contains the given element. If the element doesn't exist, the set is empty.
+func (sets *DisjointSets) SetOf(i interface{}) Set {
+	sets.Lock()
+	defer sets.Unlock()
+	class := sets.hasher.derived(0)
+	h := sets.hasher.hashFor(i)
+	if _, ok := sets.parent[h]; ok {
+		root := sets.find(h)
+		for other := range sets.parent {
+			if sets.find(other) == root {
+				class.addWithHash(sets.elements[other], other)
+			}
+		}
+	}
+	return class.ThreadSafe()
+}
+
+// Partition provides all disjoint sets as a set of sets, which can be compared with Equal to other partitions.
+func (sets *DisjointSets) Partition() Set {
+	sets.Lock()
+	defer sets.Unlock()
+	classes := make(map[uint64]*threadUnsafeSet, sets.count)
+	for h := range sets.parent {
+		root := sets.find(h)
+		class, ok := classes[root]
+		if !ok {
+			class = sets.hasher.derived(0)
+			classes[root] = class
+		}
+		class.addWithHash(sets.elements[h], h)
+	}
+	partition := sets.hasher.derived(len(classes))
+	for _, class := range classes {
+		partition.addWithHash(class.ThreadSafe(), class.Hash())
+	}
+	return partition.ThreadSafe()
+}
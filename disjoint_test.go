@@ -0,0 +1,70 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_DisjointSets(t *testing.T) {
+	sets := NewDisjointSets("alice", "bob", "carol", "dave")
+	if sets.Count() != 4 || sets.Len() != 4 {
+		t.Fatalf("expected 4 singletons, got %d sets of %d elements", sets.Count(), sets.Len())
+	}
+
+	if !sets.Union("alice", "bob") || !sets.Union("carol", "erin") || !sets.Union("bob", "erin") {
+		t.Error("merging disjoint sets should report true")
+	}
+	if sets.Union("alice", "carol") {
+		t.Error("merging connected elements should report false")
+	}
+
+	if sets.Count() != 2 || sets.Len() != 5 {
+		t.Errorf("expected 2 sets of 5 elements, got %d sets of %d elements", sets.Count(), sets.Len())
+	}
+	if !sets.Connected("alice", "erin") || sets.Connected("alice", "dave") || sets.Connected("alice", "frank") {
+		t.Error("connectivity is wrong")
+	}
+
+	a, _ := sets.Find("alice")
+	e, _ := sets.Find("erin")
+	if a != e {
+		t.Errorf("connected elements should share the representative, got %v and %v", a, e)
+	}
+	if _, ok := sets.Find("frank"); ok {
+		t.Error("unknown element should not be found")
+	}
+
+	if class := sets.SetOf("carol"); !class.Equal(NewSet("alice", "bob", "carol", "erin")) {
+		t.Errorf("unexpected set %v", class)
+	}
+	want := NewSet(NewSet("alice", "bob", "carol", "erin"), NewSet("dave"))
+	if partition := sets.Partition(); !partition.Equal(want) {
+		t.Errorf("expected partition %v, got %v", want, partition)
+	}
+}
+
+func Test_DisjointSetsPartitionEqual(t *testing.T) {
+	a, b := NewDisjointSets(), NewDisjointSets()
+	a.Union(1, 2)
+	a.Union(3, 4)
+	b.Union(1, 3)
+	b.Union(2, 4)
+	if a.Partition().Equal(b.Partition()) {
+		t.Errorf("different groupings of the same elements should differ, got %v and %v", a.Partition(), b.Partition())
+	}
+	b.Union(1, 2)
+	a.Union(1, 3)
+	if !a.Partition().Equal(b.Partition()) {
+		t.Errorf("equal groupings should be equal, got %v and %v", a.Partition(), b.Partition())
+	}
+}
+
+func Test_DisjointSetsDeepEquality(t *testing.T) {
+	sets := NewDisjointSets()
+	sets.Union(NewSet(1, 2), OrderedPair{First: 1, Second: 2})
+	if !sets.Connected(NewSet(2, 1), OrderedPair{First: 1, Second: 2}) {
+		t.Error("elements should be identified by their hashes")
+	}
+	if sets.Count() != 1 {
+		t.Errorf("expected 1 set, got %d", sets.Count())
+	}
+}
@@ -0,0 +1,437 @@
+package mapset
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Operator is a binary operator of a set expression.
+type Operator int
+
+const (
+	// OpUnion is the union operator "|".
+	OpUnion Operator = iota
+	// OpIntersect is the intersection operator "&".
+	OpIntersect
+	// OpDifference is the difference operator "-".
+	OpDifference
+	// OpSymmetricDifference is the symmetric difference operator "^".
+	OpSymmetricDifference
+	// OpSubset is the subset predicate "<=".
+	OpSubset
+	// OpProperSubset is the proper subset predicate "<".
+	OpProperSubset
+	// OpSuperset is the superset predicate ">=".
+	OpSuperset
+	// OpProperSuperset is the proper superset predicate ">".
+	OpProperSuperset
+	// OpEqual is the equality predicate "==".
+	OpEqual
+	// OpNotEqual is the inequality predicate "!=".
+	OpNotEqual
+)
+
+var operatorSymbols = [...]string{
+	OpUnion:               "|",
+	OpIntersect:           "&",
+	OpDifference:          "-",
+	OpSymmetricDifference: "^",
+	OpSubset:              "<=",
+	OpProperSubset:        "<",
+	OpSuperset:            ">=",
+	OpProperSuperset:      ">",
+	OpEqual:               "==",
+	OpNotEqual:            "!=",
+}
+
+// String provides the symbol of the operator.
+func (op Operator) String() string {
+	if op < 0 || int(op) >= len(operatorSymbols) {
+		return fmt.Sprintf("Operator(%d)", int(op))
+	}
+	return operatorSymbols[op]
+}
+
+// IsPredicate determines if the operator compares two sets instead of combining them.
+func (op Operator) IsPredicate() bool {
+	return op >= OpSubset
+}
+
+// Expr is a node of the abstract syntax tree of a set expression.
+type Expr interface {
+	// Pos provides the byte offset of the expression in the source.
+	Pos() int
+	// String provides the expression in fully parenthesized form.
+	String() string
+}
+
+// Ident is a reference to a named set.
+type Ident struct {
+	NamePos int
+	Name    string
+}
+
+// Pos provides the byte offset of the name in the source.
+func (x *Ident) Pos() int {
+	return x.NamePos
+}
+
+// String provides the name.
+func (x *Ident) String() string {
+	return x.Name
+}
+
+// BinaryExpr combines or compares two set expressions.
+type BinaryExpr struct {
+	X     Expr
+	OpPos int
+	Op    Operator
+	Y     Expr
+}
+
+// Pos provides the byte offset of the left operand in the source.
+func (x *BinaryExpr) Pos() int {
+	return x.X.Pos()
+}
+
+// String provides the expression in fully parenthesized form.
+func (x *BinaryExpr) String() string {
+	if x.Op.IsPredicate() {
+		return fmt.Sprintf("%v %v %v", x.X, x.Op, x.Y)
+	}
+	return fmt.Sprintf("(%v %v %v)", x.X, x.Op, x.Y)
+}
+
+// SyntaxError describes an invalid set expression.
+type SyntaxError struct {
+	// Pos is the byte offset of the error in the source.
+	Pos int
+	// Msg describes the error.
+	Msg string
+}
+
+// Error formats the error with its 1-based column.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// EvalError describes a set expression that can't be evaluated in the given environment.
+type EvalError struct {
+	// Pos is the byte offset of the failing expression in the source.
+	Pos int
+	// Msg describes the error.
+	Msg string
+}
+
+// Error formats the error with its 1-based column.
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("evaluation error at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// ParseExpr parses a set expression over named sets.
+//
+// Set names consist of letters, digits, underscores, and dots. They are combined with the operators
+// "-" (difference), "&" (intersection), "^" (symmetric difference), and "|" (union), in the order of their precedence
+// from the highest to the lowest one, like in Python. Parentheses group subexpressions.
+// The whole expression may compare two set expressions with one of the predicates
+// "<=" (subset), "<" (proper subset), ">=" (superset), ">" (proper superset), "==" (equal), and "!=" (not equal).
+//
+// For example, "(admins | editors) - suspended" or "admins <= staff".
+func ParseExpr(src string) (Expr, error) {
+	p := &parser{src: src}
+	p.next()
+	expr, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != tokEOF {
+		return nil, p.errorf("unexpected %s", p.describe())
+	}
+	return expr, nil
+}
+
+// Eval parses and evaluates the given set expression with the named sets of the given environment.
+// The result is a Set for set expressions and a bool for predicates.
+func Eval(src string, env map[string]Set) (interface{}, error) {
+	expr, err := ParseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := expr.(*BinaryExpr); ok && b.Op.IsPredicate() {
+		return EvalBool(expr, env)
+	}
+	return EvalSet(expr, env)
+}
+
+// EvalSet evaluates the given set expression with the named sets of the given environment.
+// The expression is evaluated as lazy view and only materialized once.
+func EvalSet(expr Expr, env map[string]Set) (Set, error) {
+	view, err := evalView(expr, env)
+	if err != nil {
+		return nil, err
+	}
+	return view.Materialize(), nil
+}
+
+// EvalBool evaluates the given predicate with the named sets of the given environment.
+func EvalBool(expr Expr, env map[string]Set) (bool, error) {
+	b, ok := expr.(*BinaryExpr)
+	if !ok || !b.Op.IsPredicate() {
+		return false, &EvalError{Pos: expr.Pos(), Msg: fmt.Sprintf("%v is not a predicate", expr)}
+	}
+	x, err := EvalSet(b.X, env)
+	if err != nil {
+		return false, err
+	}
+	y, err := EvalSet(b.Y, env)
+	if err != nil {
+		return false, err
+	}
+	switch b.Op {
+	case OpSubset:
+		return x.IsSubset(y), nil
+	case OpProperSubset:
+		return x.IsProperSubset(y), nil
+	case OpSuperset:
+		return x.IsSuperset(y), nil
+	case OpProperSuperset:
+		return x.IsProperSuperset(y), nil
+	case OpEqual:
+		return x.Equal(y), nil
+	default:
+		return !x.Equal(y), nil
+	}
+}
+
+func evalView(expr Expr, env map[string]Set) (View, error) {
+	switch x := expr.(type) {
+	case *Ident:
+		set, ok := env[x.Name]
+		if !ok {
+			return nil, &EvalError{Pos: x.NamePos, Msg: fmt.Sprintf("undefined set %q", x.Name)}
+		}
+		return ViewOf(set), nil
+	case *BinaryExpr:
+		if x.Op.IsPredicate() {
+			return nil, &EvalError{Pos: x.OpPos, Msg: fmt.Sprintf("predicate %v cannot be used as set", x.Op)}
+		}
+		left, err := evalView(x.X, env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evalView(x.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		switch x.Op {
+		case OpUnion:
+			return UnionView(left, right), nil
+		case OpIntersect:
+			return IntersectView(left, right), nil
+		case OpDifference:
+			return DifferenceView(left, right), nil
+		default:
+			return SymmetricDifferenceView(left, right), nil
+		}
+	default:
+		return nil, &EvalError{Pos: expr.Pos(), Msg: fmt.Sprintf("unsupported expression %T", expr)}
+	}
+}
+
+type token int
+
+const (
+	tokEOF token = iota
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokIllegal
+)
+
+type parser struct {
+	src string
+	// current token
+	tok token
+	pos int
+	lit string
+	op  Operator
+	// read offset
+	offset int
+}
+
+// next scans the next token.
+func (p *parser) next() {
+	for p.offset < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.offset:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.offset += size
+	}
+	p.pos = p.offset
+	if p.offset >= len(p.src) {
+		p.tok, p.lit = tokEOF, ""
+		return
+	}
+
+	r, size := utf8.DecodeRuneInString(p.src[p.offset:])
+	if isIdentRune(r) {
+		for p.offset < len(p.src) {
+			r, size = utf8.DecodeRuneInString(p.src[p.offset:])
+			if !isIdentRune(r) {
+				break
+			}
+			p.offset += size
+		}
+		p.tok, p.lit = tokIdent, p.src[p.pos:p.offset]
+		return
+	}
+
+	p.offset += size
+	p.tok, p.lit = tokOperator, string(r)
+	switch r {
+	case '(':
+		p.tok = tokLParen
+	case ')':
+		p.tok = tokRParen
+	case '|':
+		p.op = OpUnion
+	case '&':
+		p.op = OpIntersect
+	case '-':
+		p.op = OpDifference
+	case '^':
+		p.op = OpSymmetricDifference
+	case '<', '>', '=', '!':
+		double := p.offset < len(p.src) && p.src[p.offset] == '='
+		if double {
+			p.offset++
+			p.lit = p.src[p.pos:p.offset]
+		}
+		switch {
+		case r == '<' && double:
+			p.op = OpSubset
+		case r == '<':
+			p.op = OpProperSubset
+		case r == '>' && double:
+			p.op = OpSuperset
+		case r == '>':
+			p.op = OpProperSuperset
+		case r == '=' && double:
+			p.op = OpEqual
+		case r == '!' && double:
+			p.op = OpNotEqual
+		default:
+			p.tok = tokIllegal
+		}
+	default:
+		p.tok = tokIllegal
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describe provides a description of the current token for error messages.
+func (p *parser) describe() string {
+	switch p.tok {
+	case tokEOF:
+		return "end of expression"
+	case tokIdent:
+		return fmt.Sprintf("set name %q", p.lit)
+	case tokIllegal:
+		return fmt.Sprintf("character %q", p.lit)
+	default:
+		return fmt.Sprintf("%q", p.lit)
+	}
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	x, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok == tokOperator && p.op.IsPredicate() {
+		op, opPos := p.op, p.pos
+		p.next()
+		y, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok == tokOperator && p.op.IsPredicate() {
+			return nil, p.errorf("predicates cannot be chained")
+		}
+		return &BinaryExpr{X: x, OpPos: opPos, Op: op, Y: y}, nil
+	}
+	return x, nil
+}
+
+// precedences of the set operators from the lowest to the highest one
+var precedences = [][]Operator{
+	{OpUnion},
+	{OpSymmetricDifference},
+	{OpIntersect},
+	{OpDifference},
+}
+
+func (p *parser) parseBinary(level int) (Expr, error) {
+	if level == len(precedences) {
+		return p.parsePrimary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == tokOperator && p.atLevel(level) {
+		op, opPos := p.op, p.pos
+		p.next()
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{X: x, OpPos: opPos, Op: op, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) atLevel(level int) bool {
+	for _, op := range precedences[level] {
+		if p.op == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	switch p.tok {
+	case tokIdent:
+		ident := &Ident{NamePos: p.pos, Name: p.lit}
+		p.next()
+		return ident, nil
+	case tokLParen:
+		lparen := p.pos
+		p.next()
+		x, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != tokRParen {
+			if p.tok == tokEOF {
+				return nil, &SyntaxError{Pos: lparen, Msg: "unclosed parenthesis"}
+			}
+			return nil, p.errorf("expected \")\", found %s", p.describe())
+		}
+		p.next()
+		return x, nil
+	default:
+		return nil, p.errorf("expected set name or \"(\", found %s", p.describe())
+	}
+}
@@ -0,0 +1,112 @@
+package mapset
+
+import "testing"
+
+func Test_ParseExpr(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "admins", want: "admins"},
+		{src: "(admins | editors) - suspended", want: "((admins | editors) - suspended)"},
+		{src: "a | b & c", want: "(a | (b & c))"},
+		{src: "a & b - c", want: "(a & (b - c))"},
+		{src: "a ^ b | c ^ d", want: "((a ^ b) | (c ^ d))"},
+		{src: "a - b - c", want: "((a - b) - c)"},
+		{src: "a | b <= c", want: "(a | b) <= c"},
+		{src: "team.admins_2 != team.editors", want: "team.admins_2 != team.editors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := ParseExpr(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expr.String() != tt.want {
+				t.Errorf("ParseExpr() = %v, want %v", expr, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseExprErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{src: "", pos: 0},
+		{src: "a |", pos: 3},
+		{src: "(a | b", pos: 0},
+		{src: "a b", pos: 2},
+		{src: "a | $b", pos: 4},
+		{src: "a = b", pos: 2},
+		{src: "a <= b <= c", pos: 7},
+		{src: "(a <= b)", pos: 3},
+		{src: "a | )", pos: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseExpr(tt.src)
+			syntaxErr, ok := err.(*SyntaxError)
+			if !ok {
+				t.Fatalf("expected syntax error, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("error %v at position %d, want %d", err, syntaxErr.Pos, tt.pos)
+			}
+		})
+	}
+}
+
+func Test_Eval(t *testing.T) {
+	env := map[string]Set{
+		"admins":    NewSet("alice", "bob"),
+		"editors":   NewSet("bob", "carol", "dave"),
+		"suspended": NewSet("dave"),
+		"staff":     NewSet("alice", "bob", "carol", "dave", "eve"),
+	}
+	tests := []struct {
+		src  string
+		want interface{}
+	}{
+		{src: "(admins | editors) - suspended", want: NewSet("alice", "bob", "carol")},
+		{src: "admins & editors", want: NewSet("bob")},
+		{src: "admins ^ editors", want: NewSet("alice", "carol", "dave")},
+		{src: "admins <= staff", want: true},
+		{src: "admins < admins", want: false},
+		{src: "staff >= editors - suspended", want: true},
+		{src: "staff > staff", want: false},
+		{src: "admins | editors == staff - suspended", want: false},
+		{src: "admins != editors", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := Eval(tt.src, env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, ok := tt.want.(Set); ok {
+				assertEqual(got.(Set), want, t)
+			} else if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_EvalErrors(t *testing.T) {
+	env := map[string]Set{"admins": NewSet("alice")}
+
+	_, err := Eval("admins | editors", env)
+	if evalErr, ok := err.(*EvalError); !ok || evalErr.Pos != 9 {
+		t.Errorf("expected evaluation error at position 9, got %v", err)
+	}
+
+	expr, err := ParseExpr("admins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EvalBool(expr, env); err == nil {
+		t.Error("a set expression should not be evaluated as predicate")
+	}
+}
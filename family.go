@@ -0,0 +1,152 @@
+package mapset
+
+import (
+	"fmt"
+)
+
+// members provides the sets of the given family. It panics if the family contains elements that are no sets.
+func members(family Set) []Set {
+	sets := make([]Set, 0, family.Cardinality())
+	family.Each(func(elem interface{}) bool {
+		set, ok := elem.(Set)
+		if !ok {
+			panic(fmt.Sprintf("family requires Set elements, got %v of type %T", elem, elem))
+		}
+		sets = append(sets, set)
+		return false
+	})
+	return sets
+}
+
+// memberHashes provides the hashes of the given sets.
+// Looking up the hashes of new sets doesn't cache them in the family like Contains.
+func memberHashes(sets []Set) map[uint64]bool {
+	hashes := make(map[uint64]bool, len(sets))
+	for _, set := range sets {
+		hashes[set.Hash()] = true
+	}
+	return hashes
+}
+
+// BigUnion provides the union of all sets of the given family, e.g., of a PowerSet.
+// The family of no sets has an empty union. It panics if the family contains elements that are no sets.
+func BigUnion(family Set) Set {
+	return UnionAll(members(family)...)
+}
+
+// BigIntersection provides the intersection of all sets of the given family.
+// Since the intersection of no sets has no universe to refer to, it is empty, too.
+// It panics if the family contains elements that are no sets.
+func BigIntersection(family Set) Set {
+	return IntersectAll(members(family)...)
+}
+
+// Minimal provides the sets of the given family that have no proper subset in the family.
+// It panics if the family contains elements that are no sets.
+func Minimal(family Set) Set {
+	sets := members(family)
+	minimal := derivedFrom(family, 0)
+	for _, a := range sets {
+		if !anyOf(sets, func(b Set) bool { return b.IsProperSubset(a) }) {
+			minimal.addWithHash(a, a.Hash())
+		}
+	}
+	return resultLike(family, minimal)
+}
+
+// Maximal provides the sets of the given family that have no proper superset in the family.
+// It panics if the family contains elements that are no sets.
+func Maximal(family Set) Set {
+	sets := members(family)
+	maximal := derivedFrom(family, 0)
+	for _, a := range sets {
+		if !anyOf(sets, func(b Set) bool { return b.IsProperSuperset(a) }) {
+			maximal.addWithHash(a, a.Hash())
+		}
+	}
+	return resultLike(family, maximal)
+}
+
+func anyOf(sets []Set, predicate func(Set) bool) bool {
+	for _, set := range sets {
+		if predicate(set) {
+			return true
+		}
+	}
+	return false
+}
+
+// everyPair determines whether the given predicate holds for every pair of distinct sets.
+func everyPair(sets []Set, predicate func(a, b Set) bool) bool {
+	for i, a := range sets {
+		for _, b := range sets[i+1:] {
+			if !predicate(a, b) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsChain determines whether every two sets of the given family are comparable by inclusion.
+// It panics if the family contains elements that are no sets.
+func IsChain(family Set) bool {
+	return everyPair(members(family), func(a, b Set) bool {
+		return a.IsSubset(b) || b.IsSubset(a)
+	})
+}
+
+// IsAntichain determines whether no two sets of the given family are comparable by inclusion.
+// It panics if the family contains elements that are no sets.
+func IsAntichain(family Set) bool {
+	return everyPair(members(family), func(a, b Set) bool {
+		return !a.IsSubset(b) && !b.IsSubset(a)
+	})
+}
+
+// IsUnionClosed determines whether the union of every two sets of the given family is in the family.
+// It panics if the family contains elements that are no sets.
+func IsUnionClosed(family Set) bool {
+	sets := members(family)
+	hashes := memberHashes(sets)
+	return everyPair(sets, func(a, b Set) bool {
+		return hashes[a.Union(b).Hash()]
+	})
+}
+
+// IsIntersectionClosed determines whether the intersection of every two sets of the given family is in the family.
+// It panics if the family contains elements that are no sets.
+func IsIntersectionClosed(family Set) bool {
+	sets := members(family)
+	hashes := memberHashes(sets)
+	return everyPair(sets, func(a, b Set) bool {
+		return hashes[a.Intersect(b).Hash()]
+	})
+}
+
+// UpClosure provides all subsets of the given universe that are supersets of a set of the given family.
+// The number of sets grows exponentially with the elements of the universe that are missing from the family's sets.
+// It panics if the family contains elements that are no sets.
+func UpClosure(family, universe Set) Set {
+	closure := derivedFrom(family, 0)
+	for _, set := range members(family) {
+		for extension := range LazyPowerSet(universe.Difference(set), nil).C {
+			superset := set.Union(extension.(Set))
+			closure.addWithHash(superset, superset.Hash())
+		}
+	}
+	return resultLike(family, closure)
+}
+
+// DownClosure provides all subsets of the sets of the given family.
+// The number of sets grows exponentially with the cardinality of the family's sets.
+// It panics if the family contains elements that are no sets.
+func DownClosure(family Set) Set {
+	closure := derivedFrom(family, 0)
+	for _, set := range members(family) {
+		for subset := range LazyPowerSet(set, nil).C {
+			closure.addWithHash(subset, subset.(Set).Hash())
+		}
+	}
+	return resultLike(family, closure)
+}
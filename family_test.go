@@ -0,0 +1,81 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_FamilyBigOperations(t *testing.T) {
+	family := NewSet(NewSet(1, 2, 3), NewSet(2, 3, 4), NewSet(3, 5))
+	if union := BigUnion(family); !union.Equal(NewSet(1, 2, 3, 4, 5)) {
+		t.Errorf("unexpected union %v", union)
+	}
+	if intersection := BigIntersection(family); !intersection.Equal(NewSet(3)) {
+		t.Errorf("unexpected intersection %v", intersection)
+	}
+	if !BigUnion(NewSet(1, 2).PowerSet()).Equal(NewSet(1, 2)) {
+		t.Error("the union of a power set should be the set itself")
+	}
+	if !BigUnion(NewSet()).Empty() || !BigIntersection(NewSet()).Empty() {
+		t.Error("operations on an empty family should be empty")
+	}
+}
+
+func Test_FamilyOrder(t *testing.T) {
+	family := NewSet(NewSet(1), NewSet(1, 2), NewSet(3), NewSet(1, 2, 3))
+	if minimal := Minimal(family); !minimal.Equal(NewSet(NewSet(1), NewSet(3))) {
+		t.Errorf("unexpected minimal sets %v", minimal)
+	}
+	if maximal := Maximal(family); !maximal.Equal(NewSet(NewSet(1, 2, 3))) {
+		t.Errorf("unexpected maximal sets %v", maximal)
+	}
+
+	tests := []struct {
+		name      string
+		family    Set
+		chain     bool
+		antichain bool
+	}{
+		{"mixed", family, false, false},
+		{"chain", NewSet(NewSet(), NewSet(1), NewSet(1, 2)), true, false},
+		{"antichain", NewSet(NewSet(1, 2), NewSet(2, 3), NewSet(1, 3)), false, true},
+		{"single set", NewSet(NewSet(1)), true, true},
+	}
+	for _, tt := range tests {
+		if got := IsChain(tt.family); got != tt.chain {
+			t.Errorf("%s: IsChain() = %v, want %v", tt.name, got, tt.chain)
+		}
+		if got := IsAntichain(tt.family); got != tt.antichain {
+			t.Errorf("%s: IsAntichain() = %v, want %v", tt.name, got, tt.antichain)
+		}
+	}
+}
+
+func Test_FamilyClosures(t *testing.T) {
+	powerSet := NewSet(1, 2, 3).PowerSet()
+	if !IsUnionClosed(powerSet) || !IsIntersectionClosed(powerSet) {
+		t.Error("a power set is closed under union and intersection")
+	}
+	family := NewSet(NewSet(1, 2), NewSet(2, 3))
+	if IsUnionClosed(family) || IsIntersectionClosed(family) {
+		t.Error("the family is not closed")
+	}
+
+	down := DownClosure(family)
+	want := NewSet(NewSet(), NewSet(1), NewSet(2), NewSet(3), NewSet(1, 2), NewSet(2, 3))
+	if !down.Equal(want) || !IsIntersectionClosed(down) {
+		t.Errorf("unexpected down closure %v", down)
+	}
+
+	up := UpClosure(NewSet(NewSet(1)), NewSet(1, 2, 3))
+	want = NewSet(NewSet(1), NewSet(1, 2), NewSet(1, 3), NewSet(1, 2, 3))
+	if !up.Equal(want) || !IsUnionClosed(up) {
+		t.Errorf("unexpected up closure %v", up)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("family of non-sets should panic")
+		}
+	}()
+	BigUnion(NewSet(1))
+}
@@ -0,0 +1,51 @@
+package mapset
+
+// FingerprintScheme selects how the element hashes of a set are combined to its fingerprint.
+// Sets with the same cardinality and the same fingerprint are considered equal.
+type FingerprintScheme int
+
+const (
+	// XORFingerprint combines the element hashes by XOR.
+	// It is the fastest scheme, but two different sets collide whenever the XORs of their element hashes match.
+	XORFingerprint FingerprintScheme = iota
+	// SumXORFingerprint combines the XOR of the element hashes with their sum modulo 2^64.
+	// A collision requires both the XORs and the sums to match.
+	SumXORFingerprint
+	// MultisetFingerprint is a 128-bit additive multiset hash that sums two independently mixed variants of every
+	// element hash modulo 2^64. It is the most collision-resistant scheme.
+	MultisetFingerprint
+)
+
+// multisetSeed decorrelates the second word of the multiset fingerprint from the first one.
+const multisetSeed = 0x9e3779b97f4a7c15
+
+// Fingerprint is the 128-bit fingerprint of a set. Its words depend on the FingerprintScheme of the set.
+type Fingerprint [2]uint64
+
+// add includes the given element hash in the fingerprint.
+func (f *Fingerprint) add(scheme FingerprintScheme, h uint64) {
+	switch scheme {
+	case SumXORFingerprint:
+		f[0] ^= h
+		f[1] += h
+	case MultisetFingerprint:
+		f[0] += mix64(h)
+		f[1] += mix64(h ^ multisetSeed)
+	default:
+		f[0] ^= h
+	}
+}
+
+// remove excludes the given element hash from the fingerprint.
+func (f *Fingerprint) remove(scheme FingerprintScheme, h uint64) {
+	switch scheme {
+	case SumXORFingerprint:
+		f[0] ^= h
+		f[1] -= h
+	case MultisetFingerprint:
+		f[0] -= mix64(h)
+		f[1] -= mix64(h ^ multisetSeed)
+	default:
+		f[0] ^= h
+	}
+}
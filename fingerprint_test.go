@@ -0,0 +1,134 @@
+package mapset
+
+import (
+	"testing"
+)
+
+// fixedHash is an element that determines its own hash, which allows to construct fingerprint collisions.
+type fixedHash uint64
+
+func (h fixedHash) Hash() uint64 {
+	return uint64(h)
+}
+
+func Test_FingerprintCollision(t *testing.T) {
+	// 1^2 == 5^6 but 1+2 != 5+6, and 1+7 == 2+6 but 1^7 != 2^6
+	tests := []struct {
+		name    string
+		options SetOptions
+		a       []interface{}
+		b       []interface{}
+		want    bool
+	}{
+		{
+			name:    "xor collides",
+			options: SetOptions{},
+			a:       []interface{}{fixedHash(1), fixedHash(2)},
+			b:       []interface{}{fixedHash(5), fixedHash(6)},
+			want:    true,
+		},
+		{
+			name:    "strict xor does not collide",
+			options: SetOptions{StrictEqual: true},
+			a:       []interface{}{fixedHash(1), fixedHash(2)},
+			b:       []interface{}{fixedHash(5), fixedHash(6)},
+			want:    false,
+		},
+		{
+			name:    "sum and xor does not collide",
+			options: SetOptions{Fingerprint: SumXORFingerprint},
+			a:       []interface{}{fixedHash(1), fixedHash(2)},
+			b:       []interface{}{fixedHash(5), fixedHash(6)},
+			want:    false,
+		},
+		{
+			name:    "sum and xor does not collide on equal sums",
+			options: SetOptions{Fingerprint: SumXORFingerprint},
+			a:       []interface{}{fixedHash(1), fixedHash(7)},
+			b:       []interface{}{fixedHash(2), fixedHash(6)},
+			want:    false,
+		},
+		{
+			name:    "multiset does not collide",
+			options: SetOptions{Fingerprint: MultisetFingerprint},
+			a:       []interface{}{fixedHash(1), fixedHash(2)},
+			b:       []interface{}{fixedHash(5), fixedHash(6)},
+			want:    false,
+		},
+		{
+			name:    "multiset equal sets",
+			options: SetOptions{Fingerprint: MultisetFingerprint, StrictEqual: true},
+			a:       []interface{}{"one", 2, NewSet(3)},
+			b:       []interface{}{NewSet(3), 2, "one"},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.options.New(tt.a...)
+			b := tt.options.New(tt.b...)
+			if got := a.Equal(b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FingerprintHashCompatibility(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	if a.Hash() != mix64(a.Fingerprint()[0]) {
+		t.Error("the hash of the xor scheme should equal the mixed xor of the element hashes")
+	}
+}
+
+func Test_FingerprintNestedSets(t *testing.T) {
+	// both families have the same XOR of all nested element hashes
+	a := NewSet(NewSet(1, 2), NewSet(3, 4))
+	b := NewSet(NewSet(1, 3), NewSet(2, 4))
+	if a.Equal(b) || a.Hash() == b.Hash() {
+		t.Error("nested sets should not cancel each other out")
+	}
+}
+
+func Test_FingerprintMixedSchemes(t *testing.T) {
+	a := SetOptions{Fingerprint: MultisetFingerprint}.New(1, 2, 3)
+	b := SetOptions{Fingerprint: SumXORFingerprint}.New(3, 2, 1)
+	c := NewSet(3, 2, 4)
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("sets with different schemes should be compared element-wise")
+	}
+	if a.Equal(c) || c.Equal(a) {
+		t.Error("sets with different schemes and elements should not be equal")
+	}
+	if a.Hash() != b.Hash() || a.Hash() != NewSet(1, 2, 3).Hash() {
+		t.Error("equal sets with different schemes should have equal hashes")
+	}
+	if !NewSet(a).Contains(NewSet(1, 2, 3)) || !NewSet(NewSet(1, 2, 3)).Contains(b) {
+		t.Error("equal sets with different schemes should replace each other as elements")
+	}
+}
+
+func Test_FingerprintRemove(t *testing.T) {
+	for _, scheme := range []FingerprintScheme{XORFingerprint, SumXORFingerprint, MultisetFingerprint} {
+		options := SetOptions{Fingerprint: scheme}
+		a := options.New(1, 2, 3)
+		want := a.Fingerprint()
+
+		a.Remove(4)
+		if a.Fingerprint() != want {
+			t.Errorf("removing an absent element should not change the fingerprint of scheme %d", scheme)
+		}
+
+		a.Add(4)
+		a.Remove(4)
+		if a.Fingerprint() != want {
+			t.Errorf("adding and removing an element should restore the fingerprint of scheme %d", scheme)
+		}
+
+		a.Remove(1, 2, 3)
+		if a.Fingerprint() != (Fingerprint{}) {
+			t.Errorf("the fingerprint of an empty set of scheme %d should be zero", scheme)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package mapset
+
+import (
+	"github.com/OneOfOne/xxhash"
+	"github.com/gofunky/hashstructure"
+	"sync"
+)
+
+// mix64 scrambles the given element hash using the finalizer of splitmix64.
+// Element hashes of nested sets are plain XOR states and therefore not evenly distributed,
+// mixing them first makes them suitable for bucketing and additive digests.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// sequenceHashers provides the hash options of hashSequence, so that hashing a tuple doesn't allocate a hasher.
+var sequenceHashers = sync.Pool{
+	New: func() interface{} {
+		return &hashstructure.HashOptions{Hasher: xxhash.New64()}
+	},
+}
+
+// hashSequence hashes the given elements in order, e.g., the components of a tuple.
+// Elements are always hashed with the default hasher of a set, since the Hash method of a tuple doesn't know the
+// options of the set that contains it. Hence, the hashes of tuples and pairs are fixed even if SetOptions.Hasher is set.
+func hashSequence(elements ...interface{}) uint64 {
+	options := sequenceHashers.Get().(*hashstructure.HashOptions)
+	defer sequenceHashers.Put(options)
+	h := mix64(uint64(len(elements)))
+	for _, elem := range elements {
+		eh, err := hashstructure.Hash(elem, options)
+		if err != nil {
+			panic(err)
+		}
+		h = mix64(h ^ eh)
+	}
+	return h
+}
@@ -0,0 +1,312 @@
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// HashMap is a map whose keys are hashed like set elements.
+// Unlike builtin maps, it accepts keys such as structs with slices, slices, or sets.
+type HashMap interface {
+	// Get provides the value of the given key. If the key doesn't exist, ok is false.
+	Get(key interface{}) (value interface{}, ok bool)
+
+	// Put associates the given value with the given key and replaces the previous value.
+	Put(key, value interface{})
+
+	// Delete removes the given keys and their values.
+	Delete(keys ...interface{})
+
+	// ContainsKey determines whether the given key exists.
+	ContainsKey(key interface{}) bool
+
+	// Len provides the number of keys.
+	Len() int
+
+	// Clear removes all keys and values.
+	Clear()
+
+	// Clone provides a shallow copy of the map.
+	Clone() HashMap
+
+	// Keys provides the set of all keys. It reuses the key hashes of the map instead of hashing the keys again.
+	// The set is thread-safe unless the map was created with the Unsafe option.
+	Keys() Set
+
+	// Values provides the values of all keys.
+	Values() []interface{}
+
+	// Each iterates over the keys and values and executes the passed func against each pair.
+	// If passed func returns true, stop iteration eagerly.
+	Each(func(key, value interface{}) bool)
+
+	// UpdateHash recalculates the hashes of all keys.
+	// Call it after mutable keys such as sets were modified. It returns the number of updated keys.
+	// If a modified key equals another key now, only one of them and its value remains.
+	UpdateHash() (updated int)
+
+	// String provides the keys and values in the form "Map{key: value, ...}".
+	String() string
+
+	// MarshalJSON creates a JSON array of [key, value] arrays.
+	MarshalJSON() ([]byte, error)
+
+	// UnmarshalJSON adds the [key, value] arrays of a JSON array. Numbers are decoded as json.Number.
+	UnmarshalJSON(p []byte) error
+}
+
+// NewHashMap creates an empty map. Operations on the resulting map are thread-safe.
+func NewHashMap() HashMap {
+	options := SetOptions{Cache: true}
+	return options.NewHashMap()
+}
+
+// NewUnsafeHashMap creates an empty map. Operations on the resulting map are not thread-safe.
+func NewUnsafeHashMap() HashMap {
+	options := SetOptions{Cache: true, Unsafe: true}
+	return options.NewHashMap()
+}
+
+// NewHashMap creates a new map whose keys are hashed with the given options.
+func (o SetOptions) NewHashMap() HashMap {
+	m := threadUnsafeMap{
+		keys:   o.newThreadUnsafeSet(),
+		values: make(map[uint64]interface{}),
+	}
+	if o.Unsafe {
+		return &m
+	}
+	return &threadSafeMap{threadUnsafeMap: m}
+}
+
+type threadUnsafeMap struct {
+	keys   threadUnsafeSet
+	values map[uint64]interface{}
+}
+
+func (m *threadUnsafeMap) Get(key interface{}) (value interface{}, ok bool) {
+	value, ok = m.values[m.keys.hashFor(key)]
+	return
+}
+
+func (m *threadUnsafeMap) Put(key, value interface{}) {
+	h := m.keys.hashFor(key)
+	m.keys.addWithHash(key, h)
+	m.values[h] = value
+}
+
+func (m *threadUnsafeMap) Delete(keys ...interface{}) {
+	for _, key := range keys {
+		h := m.keys.hashFor(key)
+		m.keys.removeWithHash(h)
+		delete(m.values, h)
+	}
+}
+
+func (m *threadUnsafeMap) ContainsKey(key interface{}) bool {
+	_, ok := m.values[m.keys.hashFor(key)]
+	return ok
+}
+
+func (m *threadUnsafeMap) Len() int {
+	return len(m.values)
+}
+
+func (m *threadUnsafeMap) Clear() {
+	m.keys.Clear()
+	m.values = make(map[uint64]interface{})
+}
+
+func (m *threadUnsafeMap) Clone() HashMap {
+	return m.clone()
+}
+
+func (m *threadUnsafeMap) clone() *threadUnsafeMap {
+	values := make(map[uint64]interface{}, len(m.values))
+	for h, value := range m.values {
+		values[h] = value
+	}
+	return &threadUnsafeMap{
+		keys:   *m.keys.Clone().(*threadUnsafeSet),
+		values: values,
+	}
+}
+
+func (m *threadUnsafeMap) Keys() Set {
+	return m.keySet()
+}
+
+// keySet copies the keys with their hashes to a set with its own hash cache.
+func (m *threadUnsafeMap) keySet() *threadUnsafeSet {
+	keys := m.keys.derived(len(m.keys.anyMap))
+	for h, key := range m.keys.anyMap {
+		keys.addWithHash(key, h)
+	}
+	return keys
+}
+
+func (m *threadUnsafeMap) Values() []interface{} {
+	values := make([]interface{}, 0, len(m.values))
+	for _, value := range m.values {
+		values = append(values, value)
+	}
+	return values
+}
+
+func (m *threadUnsafeMap) Each(cb func(key, value interface{}) bool) {
+	for h, key := range m.keys.anyMap {
+		if cb(key, m.values[h]) {
+			break
+		}
+	}
+}
+
+func (m *threadUnsafeMap) UpdateHash() int {
+	values := make(map[uint64]interface{})
+	updated := m.keys.updateHash(func(previous, current uint64) {
+		// the key set keeps the first key of a collision
+		if _, ok := values[current]; !ok {
+			values[current] = m.values[previous]
+		}
+		delete(m.values, previous)
+	})
+	for h, value := range values {
+		// the key set keeps an unchanged key if a modified key collides with it
+		if _, ok := m.values[h]; !ok && m.keys.containsHash(h) {
+			m.values[h] = value
+		}
+	}
+	return updated
+}
+
+func (m *threadUnsafeMap) String() string {
+	if len(m.values) == 0 {
+		return "Map{}"
+	}
+	items := bytes.NewBufferString("Map{")
+	for h, key := range m.keys.anyMap {
+		_, err := fmt.Fprintf(items, "%v: %v, ", key, m.values[h])
+		if err != nil {
+			panic(err)
+		}
+	}
+	items.Truncate(items.Len() - 2)
+	items.WriteString("}")
+	return items.String()
+}
+
+func (m *threadUnsafeMap) MarshalJSON() ([]byte, error) {
+	entries := make([][2]interface{}, 0, len(m.values))
+	for h, key := range m.keys.anyMap {
+		entries = append(entries, [2]interface{}{key, m.values[h]})
+	}
+	return json.Marshal(entries)
+}
+
+func (m *threadUnsafeMap) UnmarshalJSON(p []byte) error {
+	entries, err := decodeJSONArray(p)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		pair, ok := entry.([]interface{})
+		if !ok || len(pair) != 2 {
+			return fmt.Errorf("map entry requires a [key, value] array, got %v", entry)
+		}
+		m.Put(pair[0], pair[1])
+	}
+	return nil
+}
+
+type threadSafeMap struct {
+	threadUnsafeMap
+	sync.RWMutex
+}
+
+// Get locks the map for writing since hashing the key uses the hash cache and the hasher of the map.
+func (m *threadSafeMap) Get(key interface{}) (value interface{}, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+	return m.threadUnsafeMap.Get(key)
+}
+
+func (m *threadSafeMap) Put(key, value interface{}) {
+	m.Lock()
+	defer m.Unlock()
+	m.threadUnsafeMap.Put(key, value)
+}
+
+func (m *threadSafeMap) Delete(keys ...interface{}) {
+	m.Lock()
+	defer m.Unlock()
+	m.threadUnsafeMap.Delete(keys...)
+}
+
+// ContainsKey locks the map for writing since hashing the key uses the hash cache and the hasher of the map.
+func (m *threadSafeMap) ContainsKey(key interface{}) bool {
+	m.Lock()
+	defer m.Unlock()
+	return m.threadUnsafeMap.ContainsKey(key)
+}
+
+func (m *threadSafeMap) Len() int {
+	m.RLock()
+	defer m.RUnlock()
+	return m.threadUnsafeMap.Len()
+}
+
+func (m *threadSafeMap) Clear() {
+	m.Lock()
+	defer m.Unlock()
+	m.threadUnsafeMap.Clear()
+}
+
+func (m *threadSafeMap) Clone() HashMap {
+	m.RLock()
+	defer m.RUnlock()
+	return &threadSafeMap{threadUnsafeMap: *m.threadUnsafeMap.clone()}
+}
+
+func (m *threadSafeMap) Keys() Set {
+	m.RLock()
+	defer m.RUnlock()
+	return m.keySet().ThreadSafe()
+}
+
+func (m *threadSafeMap) Values() []interface{} {
+	m.RLock()
+	defer m.RUnlock()
+	return m.threadUnsafeMap.Values()
+}
+
+func (m *threadSafeMap) Each(cb func(key, value interface{}) bool) {
+	m.RLock()
+	defer m.RUnlock()
+	m.threadUnsafeMap.Each(cb)
+}
+
+func (m *threadSafeMap) UpdateHash() int {
+	m.Lock()
+	defer m.Unlock()
+	return m.threadUnsafeMap.UpdateHash()
+}
+
+func (m *threadSafeMap) String() string {
+	m.RLock()
+	defer m.RUnlock()
+	return m.threadUnsafeMap.String()
+}
+
+func (m *threadSafeMap) MarshalJSON() ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	return m.threadUnsafeMap.MarshalJSON()
+}
+
+func (m *threadSafeMap) UnmarshalJSON(p []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.threadUnsafeMap.UnmarshalJSON(p)
+}
@@ -0,0 +1,132 @@
+package mapset
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+type address struct {
+	Street string
+	Tags   []string
+}
+
+func Test_HashMapDeepKeys(t *testing.T) {
+	for name, m := range map[string]HashMap{"safe": NewHashMap(), "unsafe": NewUnsafeHashMap()} {
+		t.Run(name, func(t *testing.T) {
+			m.Put(address{Street: "Main", Tags: []string{"home"}}, 1)
+			m.Put([]int{1, 2}, 2)
+			m.Put(NewSet("a", "b"), 3)
+
+			if v, ok := m.Get(address{Street: "Main", Tags: []string{"home"}}); !ok || v != 1 {
+				t.Errorf("struct key should be found, got %v, %v", v, ok)
+			}
+			if v, ok := m.Get([]int{1, 2}); !ok || v != 2 {
+				t.Errorf("slice key should be found, got %v, %v", v, ok)
+			}
+			if v, ok := m.Get(NewSet("b", "a")); !ok || v != 3 {
+				t.Errorf("set key should be found, got %v, %v", v, ok)
+			}
+			if _, ok := m.Get([]int{2, 1}); ok {
+				t.Error("slice keys should be ordered")
+			}
+
+			m.Put([]int{1, 2}, 4)
+			m.Delete(NewSet("a", "b"), "missing")
+			if m.Len() != 2 || m.ContainsKey(NewSet("a", "b")) {
+				t.Errorf("unexpected map %v", m)
+			}
+			if v, _ := m.Get([]int{1, 2}); v != 4 {
+				t.Errorf("value should be replaced, got %v", v)
+			}
+			if len(m.Values()) != 2 {
+				t.Errorf("expected 2 values, got %v", m.Values())
+			}
+
+			clone := m.Clone()
+			clone.Clear()
+			if m.Len() != 2 || clone.Len() != 0 {
+				t.Error("clone should be independent")
+			}
+		})
+	}
+}
+
+func Test_HashMapKeys(t *testing.T) {
+	m := NewHashMap()
+	m.Put(1, "one")
+	m.Put(2, "two")
+	keys := m.Keys()
+	if !keys.Equal(NewSet(1, 2)) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	keys.Add(3)
+	if m.ContainsKey(3) {
+		t.Error("the key set should be a copy")
+	}
+	if _, ok := NewUnsafeHashMap().Keys().(*threadUnsafeSet); !ok {
+		t.Error("the key set of an unsafe map should be unsafe")
+	}
+}
+
+func Test_HashMapUpdateHash(t *testing.T) {
+	key := NewSet("foo")
+	m := NewHashMap()
+	m.Put(key, 1)
+	m.Put("bar", 2)
+
+	key.Add("baz")
+	if updated := m.UpdateHash(); updated != 1 {
+		t.Fatalf("expected 1 updated key, got %d", updated)
+	}
+	if v, ok := m.Get(NewSet("foo", "baz")); !ok || v != 1 {
+		t.Errorf("modified key should be found, got %v, %v", v, ok)
+	}
+	if v, _ := m.Get("bar"); v != 2 || m.Len() != 2 {
+		t.Error("other keys should remain")
+	}
+}
+
+func Test_HashMapJSON(t *testing.T) {
+	m := NewHashMap()
+	m.Put("a", 1)
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[["a",1]]` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+
+	decoded := NewHashMap()
+	if err := json.Unmarshal([]byte(`[["a", 1], [[1, 2], "list"]]`), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := decoded.Get([]interface{}{json.Number("1"), json.Number("2")}); !ok || v != "list" {
+		t.Errorf("array key should be decoded, got %v, %v", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`[["a"]]`), decoded); err == nil {
+		t.Error("incomplete entry should fail")
+	}
+}
+
+func Test_HashMapConcurrent(t *testing.T) {
+	m := NewHashMap()
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Put([]int{n, i}, i)
+				m.Get([]int{n, i - 1})
+				m.ContainsKey([]int{n, i - 1})
+				m.Keys().Add([]int{-n, i})
+			}
+		}(n)
+	}
+	wg.Wait()
+	if m.Len() != 800 {
+		t.Errorf("expected 800 keys, got %d", m.Len())
+	}
+}
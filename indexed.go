@@ -0,0 +1,270 @@
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUniqueViolation is returned if elements of an indexed set would share a key of a unique index.
+var ErrUniqueViolation = errors.New("unique index contains the key already")
+
+// IndexedSet is a set that maintains secondary indexes on keys that are extracted from its elements,
+// so that all elements with a given key can be looked up without scanning the set.
+// Sets that are derived from an indexed set, e.g., by Union, are not indexed.
+// Add, UnionWith, and SymmetricDifferenceWith panic with ErrUniqueViolation if they would violate a unique index.
+// Operations on an IndexedSet are thread-safe.
+type IndexedSet struct {
+	trackedSet
+	indexes map[string]*index
+}
+
+// index maps the key hashes of an index to the hashes of the elements with that key.
+type index struct {
+	key    func(interface{}) interface{}
+	unique bool
+	// hasher hashes the keys without a cache. Its hasher is stateful and only used under the write lock of the set.
+	hasher   threadUnsafeSet
+	entries  map[uint64]map[uint64]bool
+	elemKeys map[uint64]uint64
+}
+
+// NewIndexedSet creates a set without indexes that contains the given elements.
+func NewIndexedSet(elements ...interface{}) *IndexedSet {
+	options := SetOptions{Cache: true}
+	set := &IndexedSet{
+		trackedSet: trackedSet{threadSafeSet: options.newThreadSafeSet()},
+		indexes:    make(map[string]*index),
+	}
+	set.tracker = set
+	set.Add(elements...)
+	return set
+}
+
+func (set *IndexedSet) newIndex(key func(interface{}) interface{}, unique bool) *index {
+	options := set.options
+	options.Cache = false
+	return &index{
+		key:      key,
+		unique:   unique,
+		hasher:   options.newThreadUnsafeSet(),
+		entries:  make(map[uint64]map[uint64]bool),
+		elemKeys: make(map[uint64]uint64),
+	}
+}
+
+func (idx *index) insert(h uint64, elem interface{}) {
+	k := idx.hasher.hashFor(idx.key(elem))
+	if idx.entries[k] == nil {
+		idx.entries[k] = make(map[uint64]bool)
+	}
+	idx.entries[k][h] = true
+	idx.elemKeys[h] = k
+}
+
+// lookup provides the hashes of the elements with the given key.
+// It hashes the key with a hasher of its own, so that lookups are safe under a read lock.
+func (idx *index) lookup(key interface{}) map[uint64]bool {
+	return idx.entries[idx.hasher.derived(0).hashFor(key)]
+}
+
+func (idx *index) remove(h uint64) {
+	k, ok := idx.elemKeys[h]
+	if !ok {
+		return
+	}
+	delete(idx.entries[k], h)
+	if len(idx.entries[k]) == 0 {
+		delete(idx.entries, k)
+	}
+	delete(idx.elemKeys, h)
+}
+
+// violated determines whether a unique index contains a key twice.
+func (idx *index) violated() bool {
+	if !idx.unique {
+		return false
+	}
+	for _, hashes := range idx.entries {
+		if len(hashes) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// AddIndex registers an index with the given name and key function and indexes all elements.
+// It replaces an existing index with the same name.
+func (set *IndexedSet) AddIndex(name string, key func(interface{}) interface{}) {
+	set.Lock()
+	defer set.Unlock()
+	set.indexes[name] = set.build(key, false)
+}
+
+// AddUniqueIndex registers an index with the given name and key function that allows every key only once.
+// Insert returns ErrUniqueViolation and Add panics if an element with an existing key is added.
+// It returns ErrUniqueViolation and registers no index if the elements of the set already share a key.
+func (set *IndexedSet) AddUniqueIndex(name string, key func(interface{}) interface{}) error {
+	set.Lock()
+	defer set.Unlock()
+	idx := set.build(key, true)
+	if idx.violated() {
+		return ErrUniqueViolation
+	}
+	set.indexes[name] = idx
+	return nil
+}
+
+// build creates an index of all elements. It expects the set to be locked.
+func (set *IndexedSet) build(key func(interface{}) interface{}, unique bool) *index {
+	idx := set.newIndex(key, unique)
+	for h, elem := range set.threadUnsafeSet.anyMap {
+		idx.insert(h, elem)
+	}
+	return idx
+}
+
+// DropIndex removes the index with the given name.
+func (set *IndexedSet) DropIndex(name string) {
+	set.Lock()
+	defer set.Unlock()
+	delete(set.indexes, name)
+}
+
+// Indexes provides the sorted names of all indexes.
+func (set *IndexedSet) Indexes() []string {
+	set.RLock()
+	defer set.RUnlock()
+	names := make([]string, 0, len(set.indexes))
+	for name := range set.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup provides the set of all elements whose key of the given index equals the given key.
+// It panics if no index with the given name exists.
+func (set *IndexedSet) Lookup(name string, key interface{}) Set {
+	set.RLock()
+	defer set.RUnlock()
+	idx := set.index(name)
+	hashes := idx.lookup(key)
+	result := set.threadUnsafeSet.derived(len(hashes))
+	for h := range hashes {
+		result.addWithHash(set.threadUnsafeSet.anyMap[h], h)
+	}
+	return result.ThreadSafe()
+}
+
+// LookupOne provides an element whose key of the given index equals the given key, which is the only one
+// for unique indexes. If no element has the key, ok is false. It panics if no index with the given name exists.
+func (set *IndexedSet) LookupOne(name string, key interface{}) (elem interface{}, ok bool) {
+	set.RLock()
+	defer set.RUnlock()
+	idx := set.index(name)
+	for h := range idx.lookup(key) {
+		return set.threadUnsafeSet.anyMap[h], true
+	}
+	return nil, false
+}
+
+// index provides the index with the given name. It expects the set to be locked.
+func (set *IndexedSet) index(name string) *index {
+	idx, ok := set.indexes[name]
+	if !ok {
+		panic(fmt.Sprintf("indexed set has no index %q", name))
+	}
+	return idx
+}
+
+func (set *IndexedSet) begin() {}
+
+func (set *IndexedSet) end() {}
+
+// admit determines whether adding the given elements would violate a unique index. It expects the set to be locked.
+func (set *IndexedSet) admit(elements map[uint64]interface{}) error {
+	for _, idx := range set.indexes {
+		if !idx.unique {
+			continue
+		}
+		added := make(map[uint64]bool)
+		for h, elem := range elements {
+			if set.threadUnsafeSet.containsHash(h) {
+				continue
+			}
+			k := idx.hasher.hashFor(idx.key(elem))
+			if len(idx.entries[k]) > 0 || added[k] {
+				return ErrUniqueViolation
+			}
+			added[k] = true
+		}
+	}
+	return nil
+}
+
+// track adds an element and its keys. It expects the set to be locked.
+func (set *IndexedSet) track(val interface{}, h uint64) {
+	if set.threadUnsafeSet.containsHash(h) {
+		return
+	}
+	set.threadUnsafeSet.addWithHash(val, h)
+	for _, idx := range set.indexes {
+		idx.insert(h, val)
+	}
+}
+
+// untrack removes an element and its keys. It expects the set to be locked.
+func (set *IndexedSet) untrack(h uint64) {
+	set.threadUnsafeSet.removeWithHash(h)
+	for _, idx := range set.indexes {
+		idx.remove(h)
+	}
+}
+
+// compact drops the keys of elements that were removed by the underlying set. It expects the set to be locked.
+func (set *IndexedSet) compact() {
+	for _, idx := range set.indexes {
+		for h := range idx.elemKeys {
+			if !set.threadUnsafeSet.containsHash(h) {
+				idx.remove(h)
+			}
+		}
+	}
+}
+
+// Insert adds the given elements. It adds none of them and returns ErrUniqueViolation
+// if they would share a key of a unique index with each other or with an element of the set.
+func (set *IndexedSet) Insert(i ...interface{}) error {
+	return set.insert(i)
+}
+
+// UpdateHash recalculates the hashes of all elements and rebuilds all indexes, so that modified keys are found.
+// It panics with ErrUniqueViolation if modified elements share a key of a unique index now.
+// The indexes contain all elements anyway, so that the conflicting elements can be looked up and fixed.
+func (set *IndexedSet) UpdateHash() int {
+	set.Lock()
+	defer set.Unlock()
+	updated := set.threadUnsafeSet.updateHash(nil)
+	violated := false
+	for name, idx := range set.indexes {
+		set.indexes[name] = set.build(idx.key, idx.unique)
+		violated = violated || set.indexes[name].violated()
+	}
+	if violated {
+		panic(ErrUniqueViolation)
+	}
+	return updated
+}
+
+func (set *IndexedSet) cloneWith(core *threadUnsafeSet) Set {
+	clone := &IndexedSet{
+		trackedSet: trackedSet{threadSafeSet: threadSafeSet{threadUnsafeSet: *core}},
+		indexes:    make(map[string]*index, len(set.indexes)),
+	}
+	clone.tracker = clone
+	for name, idx := range set.indexes {
+		clone.indexes[name] = clone.build(idx.key, idx.unique)
+	}
+	return clone
+}
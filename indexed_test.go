@@ -0,0 +1,223 @@
+package mapset
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type member struct {
+	Name  string
+	Email string
+	Roles []string
+}
+
+func byRole(elem interface{}) interface{} {
+	return elem.(*member).Roles[0]
+}
+
+func byEmail(elem interface{}) interface{} {
+	return elem.(*member).Email
+}
+
+// parity provides the parity of ints and of numbers decoded from JSON.
+func parity(elem interface{}) interface{} {
+	if number, ok := elem.(json.Number); ok {
+		n, err := number.Int64()
+		if err != nil {
+			panic(err)
+		}
+		return int(n % 2)
+	}
+	return elem.(int) % 2
+}
+
+func Test_IndexedSetLookup(t *testing.T) {
+	alice := &member{Name: "alice", Email: "a@x", Roles: []string{"admin"}}
+	bob := &member{Name: "bob", Email: "b@x", Roles: []string{"user"}}
+	carol := &member{Name: "carol", Email: "c@x", Roles: []string{"admin"}}
+	set := NewIndexedSet(alice, bob)
+	set.AddIndex("role", byRole)
+	set.Add(carol)
+
+	if admins := set.Lookup("role", "admin"); !admins.Equal(NewSet(alice, carol)) {
+		t.Errorf("expected alice and carol, got %v", admins)
+	}
+	if !set.Lookup("role", "guest").Empty() {
+		t.Error("missing key should provide an empty set")
+	}
+
+	set.Remove(alice)
+	if admins := set.Lookup("role", "admin"); !admins.Equal(NewSet(carol)) {
+		t.Errorf("removed element should be unindexed, got %v", admins)
+	}
+	if elem, ok := set.LookupOne("role", "user"); !ok || elem != bob {
+		t.Errorf("expected bob, got %v, %v", elem, ok)
+	}
+	if !reflect.DeepEqual(set.Indexes(), []string{"role"}) {
+		t.Errorf("unexpected indexes %v", set.Indexes())
+	}
+
+	set.DropIndex("role")
+	defer func() {
+		if recover() == nil {
+			t.Error("lookup of a missing index should panic")
+		}
+	}()
+	set.Lookup("role", "admin")
+}
+
+func Test_IndexedSetUnique(t *testing.T) {
+	set := NewIndexedSet()
+	if err := set.AddUniqueIndex("email", byEmail); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	alice := &member{Name: "alice", Email: "a@x", Roles: []string{"admin"}}
+	if err := set.Insert(alice); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := set.Insert(alice); err != nil {
+		t.Errorf("inserting a contained element should succeed, got %v", err)
+	}
+
+	other := &member{Name: "other", Email: "a@x", Roles: []string{"user"}}
+	bob := &member{Name: "bob", Email: "b@x", Roles: []string{"user"}}
+	if err := set.Insert(bob, other); err != ErrUniqueViolation {
+		t.Errorf("duplicate email should be rejected, got %v", err)
+	}
+	if set.Cardinality() != 1 {
+		t.Errorf("rejected insert should add nothing, got %v", set)
+	}
+	twin := &member{Name: "twin", Email: "b@x", Roles: []string{"user"}}
+	if err := set.Insert(bob, twin); err != ErrUniqueViolation {
+		t.Errorf("new elements sharing a key should be rejected, got %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() != ErrUniqueViolation {
+				t.Error("adding a duplicate key should panic")
+			}
+		}()
+		set.Add(other)
+	}()
+
+	set.Remove(alice)
+	if err := set.Insert(other); err != nil {
+		t.Errorf("removed key should be free again, got %v", err)
+	}
+
+	set.AddIndex("role", byRole)
+	set.Add(bob)
+	if err := set.AddUniqueIndex("role", byRole); err != ErrUniqueViolation {
+		t.Errorf("shared keys should prevent a unique index, got %v", err)
+	}
+	set.Remove(bob)
+	if err := set.AddUniqueIndex("role", byRole); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func Test_IndexedSetUpdateHash(t *testing.T) {
+	alice := &member{Name: "alice", Email: "a@x", Roles: []string{"user"}}
+	bob := &member{Name: "bob", Email: "b@x", Roles: []string{"user"}}
+	set := NewIndexedSet(alice, bob)
+	set.AddIndex("role", byRole)
+	if err := set.AddUniqueIndex("email", byEmail); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	alice.Roles[0] = "admin"
+	set.UpdateHash()
+	if admins := set.Lookup("role", "admin"); !admins.Equal(NewSet(alice)) {
+		t.Errorf("modified key should be reindexed, got %v", admins)
+	}
+	if users := set.Lookup("role", "user"); !users.Equal(NewSet(bob)) {
+		t.Errorf("previous key should be unindexed, got %v", users)
+	}
+
+	bob.Email = "a@x"
+	defer func() {
+		if recover() != ErrUniqueViolation {
+			t.Error("modified duplicate key should panic")
+		}
+		if set.Lookup("email", "a@x").Cardinality() != 2 {
+			t.Error("conflicting elements should be indexed")
+		}
+	}()
+	set.UpdateHash()
+}
+
+func Test_IndexedSetInPlace(t *testing.T) {
+	set := NewIndexedSet(1, 2, 3, 4)
+	set.AddIndex("parity", parity)
+
+	set.IntersectWith(NewSet(1, 2, 3))
+	set.DifferenceWith(NewSet(3))
+	if odd := set.Lookup("parity", 1); !odd.Equal(NewSet(1)) {
+		t.Errorf("expected 1, got %v", odd)
+	}
+	set.UnionWith(NewSet(5, 6))
+	set.SymmetricDifferenceWith(NewSet(1, 7))
+	if odd := set.Lookup("parity", 1); !odd.Equal(NewSet(5, 7)) {
+		t.Errorf("expected 5 and 7, got %v", odd)
+	}
+	if err := set.UnmarshalJSON([]byte("[8]")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if elem := set.Pop(); set.Lookup("parity", parity(elem)).Contains(elem) {
+		t.Errorf("popped element %v should be unindexed", elem)
+	}
+
+	clone := set.Clone().(*IndexedSet)
+	clone.Clear()
+	if !clone.Lookup("parity", 0).Empty() || set.Lookup("parity", 0).Union(set.Lookup("parity", 1)).Cardinality() != set.Cardinality() {
+		t.Errorf("clone should have separate indexes, got %v and %v", clone, set)
+	}
+}
+
+func Test_IndexedSetUniqueInPlace(t *testing.T) {
+	set := NewIndexedSet(1, 2)
+	if err := set.AddUniqueIndex("parity", parity); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() != ErrUniqueViolation {
+				t.Error("symmetric difference with a duplicate key should panic")
+			}
+		}()
+		set.SymmetricDifferenceWith(NewSet(1, 4))
+	}()
+	if !set.Equal(NewSet(1, 2)) {
+		t.Errorf("failed symmetric difference should leave the set unchanged, got %v", set)
+	}
+	if err := set.UnmarshalJSON([]byte("[3]")); err != ErrUniqueViolation {
+		t.Errorf("decoded duplicate key should be rejected, got %v", err)
+	}
+}
+
+func Test_IndexedSetConcurrent(t *testing.T) {
+	set := NewIndexedSet()
+	set.AddIndex("parity", parity)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			set.Add(i)
+			set.Lookup("parity", i%2).Add(-i)
+			for n := 0; n < 10; n++ {
+				if _, ok := set.LookupOne("parity", i%2); !ok {
+					t.Errorf("expected an element with parity %d", i%2)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if even := set.Lookup("parity", 0); even.Cardinality() != 50 {
+		t.Errorf("expected 50 even elements, got %d", even.Cardinality())
+	}
+}
@@ -0,0 +1,729 @@
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// IntSet is a compressed bitmap set of non-negative integers in the style of roaring bitmaps.
+// The integers are split into chunks of 64K values whose lower 16 bits are stored in an array, a bitmap,
+// or a run container, whichever is the smallest. Operations between two IntSets combine the containers bitwise
+// without hashing any element.
+//
+// IntSet accepts the integer types int, int64, uint, and uint64, and it provides its elements as uint64.
+// The hashes of these types are equal, so that an IntSet equals a generic set of the same integers.
+// Other integer types have different hashes and are treated like non-integer elements.
+// Operations with generic sets use the element hashes, which are only computed once and then cached until the IntSet
+// is modified. Adding a negative or non-integer element panics.
+// Operations on an IntSet are thread-safe.
+type IntSet struct {
+	sync.RWMutex
+	chunks []chunk
+
+	cacheMu sync.Mutex
+	// cache is the generic set of all elements, it is immutable once it has been built
+	cache *threadSafeSet
+}
+
+// chunk holds the integers that share the same upper 48 bits.
+type chunk struct {
+	key       uint64
+	container container
+}
+
+// NewIntSet creates an IntSet that contains the given integers.
+func NewIntSet(values ...uint64) *IntSet {
+	set := &IntSet{}
+	for _, v := range values {
+		set.add(v)
+	}
+	return set
+}
+
+// toUint64 converts the given element to an unsigned integer if it is a non-negative integer
+// whose hash equals the one of the same uint64.
+func toUint64(i interface{}) (uint64, bool) {
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, false
+		}
+		return uint64(v.Int()), true
+	case reflect.Uint, reflect.Uint64:
+		return v.Uint(), true
+	default:
+		return 0, false
+	}
+}
+
+// intsOf provides the integer elements of the given set as IntSet, which is the set itself if it is an IntSet.
+// If the set contains elements that are no non-negative integers, complete is false.
+func intsOf(other Set) (ints *IntSet, complete bool) {
+	if o, ok := other.(*IntSet); ok {
+		return o, true
+	}
+	ints, complete = &IntSet{}, true
+	other.Each(func(elem interface{}) bool {
+		if v, ok := toUint64(elem); ok {
+			ints.add(v)
+		} else {
+			complete = false
+		}
+		return false
+	})
+	return
+}
+
+// search provides the index of the chunk with the given key or where it would be inserted.
+func (set *IntSet) search(key uint64) int {
+	return sort.Search(len(set.chunks), func(i int) bool { return set.chunks[i].key >= key })
+}
+
+func (set *IntSet) add(v uint64) {
+	key, low := v>>chunkBits, uint16(v)
+	i := set.search(key)
+	if i < len(set.chunks) && set.chunks[i].key == key {
+		set.chunks[i].container = set.chunks[i].container.add(low)
+		return
+	}
+	set.chunks = append(set.chunks, chunk{})
+	copy(set.chunks[i+1:], set.chunks[i:])
+	set.chunks[i] = chunk{key: key, container: arrayContainer{low}}
+}
+
+func (set *IntSet) remove(v uint64) {
+	key, low := v>>chunkBits, uint16(v)
+	i := set.search(key)
+	if i == len(set.chunks) || set.chunks[i].key != key {
+		return
+	}
+	set.chunks[i].container = set.chunks[i].container.remove(low)
+	if set.chunks[i].container.cardinality() == 0 {
+		set.chunks = append(set.chunks[:i], set.chunks[i+1:]...)
+	}
+}
+
+func (set *IntSet) contains(v uint64) bool {
+	key := v >> chunkBits
+	i := set.search(key)
+	return i < len(set.chunks) && set.chunks[i].key == key && set.chunks[i].container.contains(uint16(v))
+}
+
+func (set *IntSet) cardinality() (card int) {
+	for _, c := range set.chunks {
+		card += c.container.cardinality()
+	}
+	return
+}
+
+// each calls cb for all integers in ascending order until it returns true.
+func (set *IntSet) each(cb func(v uint64) bool) {
+	for _, c := range set.chunks {
+		high := c.key << chunkBits
+		if c.container.each(func(low uint16) bool {
+			return cb(high | uint64(low))
+		}) {
+			return
+		}
+	}
+}
+
+func (set *IntSet) clone() *IntSet {
+	clone := &IntSet{chunks: make([]chunk, len(set.chunks))}
+	for n, c := range set.chunks {
+		clone.chunks[n] = chunk{key: c.key, container: c.container.clone()}
+	}
+	return clone
+}
+
+// combine applies the given operation to the chunks of both sets.
+// If reuse is set, the chunks that are only in a are moved to the result instead of being copied.
+func combine(a, b *IntSet, op containerOp, reuse bool) *IntSet {
+	result := &IntSet{chunks: make([]chunk, 0, len(a.chunks)+len(b.chunks))}
+	i, j := 0, 0
+	for i < len(a.chunks) || j < len(b.chunks) {
+		switch {
+		case j == len(b.chunks) || i < len(a.chunks) && a.chunks[i].key < b.chunks[j].key:
+			if op.onlyA {
+				c := a.chunks[i]
+				if !reuse {
+					c.container = c.container.clone()
+				}
+				result.chunks = append(result.chunks, c)
+			}
+			i++
+		case i == len(a.chunks) || b.chunks[j].key < a.chunks[i].key:
+			if op.onlyB {
+				result.chunks = append(result.chunks, chunk{key: b.chunks[j].key, container: b.chunks[j].container.clone()})
+			}
+			j++
+		default:
+			if c := op.apply(a.chunks[i].container, b.chunks[j].container); c.cardinality() > 0 {
+				result.chunks = append(result.chunks, chunk{key: a.chunks[i].key, container: c})
+			}
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// lockInts read-locks the given IntSets in a consistent order. The returned function releases the locks.
+func lockInts(a, b *IntSet) (unlock func()) {
+	if a == b {
+		a.RLock()
+		return a.RUnlock
+	}
+	first, second := a, b
+	if reflect.ValueOf(first).Pointer() > reflect.ValueOf(second).Pointer() {
+		first, second = second, first
+	}
+	first.RLock()
+	second.RLock()
+	return func() {
+		second.RUnlock()
+		first.RUnlock()
+	}
+}
+
+// combined applies the given operation to this set and the given IntSet.
+func (set *IntSet) combined(other *IntSet, op containerOp) *IntSet {
+	unlock := lockInts(set, other)
+	defer unlock()
+	return combine(set, other, op, false)
+}
+
+// combineWith applies the given operation to this set in place.
+func (set *IntSet) combineWith(other *IntSet, op containerOp) {
+	if other == set {
+		set.Lock()
+		defer set.Unlock()
+	} else if reflect.ValueOf(set).Pointer() < reflect.ValueOf(other).Pointer() {
+		set.Lock()
+		defer set.Unlock()
+		other.RLock()
+		defer other.RUnlock()
+	} else {
+		other.RLock()
+		defer other.RUnlock()
+		set.Lock()
+		defer set.Unlock()
+	}
+	set.chunks = combine(set, other, op, true).chunks
+	set.cache = nil
+}
+
+// generic provides all elements as generic set. It expects the set to be read-locked.
+func (set *IntSet) generic() *threadSafeSet {
+	set.cacheMu.Lock()
+	defer set.cacheMu.Unlock()
+	if set.cache == nil {
+		options := SetOptions{Cache: true}
+		cache := options.newThreadSafeSet()
+		set.each(func(v uint64) bool {
+			cache.threadUnsafeSet.Add(v)
+			return false
+		})
+		set.cache = &cache
+	}
+	return set.cache
+}
+
+// snapshot provides all elements as generic set.
+func (set *IntSet) snapshot() *threadSafeSet {
+	set.RLock()
+	defer set.RUnlock()
+	return set.generic()
+}
+
+// RunOptimize converts the containers to run containers where they are smaller, e.g., for consecutive IDs.
+// Modifying a run container converts it back to an array or bitmap container.
+func (set *IntSet) RunOptimize() {
+	set.Lock()
+	defer set.Unlock()
+	for n, c := range set.chunks {
+		set.chunks[n].container = optimize(c.container)
+	}
+}
+
+// Containers provides the number of array, bitmap, and run containers.
+func (set *IntSet) Containers() (arrays, bitmaps, runs int) {
+	set.RLock()
+	defer set.RUnlock()
+	for _, c := range set.chunks {
+		switch c.container.(type) {
+		case arrayContainer:
+			arrays++
+		case *bitmapContainer:
+			bitmaps++
+		default:
+			runs++
+		}
+	}
+	return
+}
+
+// Min provides the smallest integer. If the set is empty, ok is false.
+func (set *IntSet) Min() (min uint64, ok bool) {
+	set.RLock()
+	defer set.RUnlock()
+	set.each(func(v uint64) bool {
+		min, ok = v, true
+		return true
+	})
+	return
+}
+
+// Max provides the largest integer. If the set is empty, ok is false.
+func (set *IntSet) Max() (max uint64, ok bool) {
+	set.RLock()
+	defer set.RUnlock()
+	if len(set.chunks) == 0 {
+		return 0, false
+	}
+	last := set.chunks[len(set.chunks)-1]
+	last.container.each(func(low uint16) bool {
+		max = last.key<<chunkBits | uint64(low)
+		return false
+	})
+	return max, true
+}
+
+// Fingerprint provides the fingerprint of the element hashes, which are computed on demand.
+func (set *IntSet) Fingerprint() Fingerprint {
+	return set.snapshot().Fingerprint()
+}
+
+// Hash provides the hash of the elements, which is equal to the one of a generic set of the same integers.
+func (set *IntSet) Hash() uint64 {
+	return set.snapshot().Hash()
+}
+
+// UpdateHash does nothing since the hashes of integers never change.
+func (set *IntSet) UpdateHash() int {
+	return 0
+}
+
+// Add adds the given integers. It panics if an element is no non-negative int, int64, uint, or uint64.
+func (set *IntSet) Add(i ...interface{}) {
+	set.Lock()
+	defer set.Unlock()
+	for _, val := range i {
+		v, ok := toUint64(val)
+		if !ok {
+			panic(fmt.Sprintf("IntSet only accepts non-negative int, int64, uint, or uint64 values, got %v (%T)", val, val))
+		}
+		set.add(v)
+	}
+	set.cache = nil
+}
+
+func (set *IntSet) Cardinality() int {
+	set.RLock()
+	defer set.RUnlock()
+	return set.cardinality()
+}
+
+func (set *IntSet) Empty() bool {
+	set.RLock()
+	defer set.RUnlock()
+	return len(set.chunks) == 0
+}
+
+// UnionWith adds all elements of the other set. It panics if the other set contains no non-negative integers.
+func (set *IntSet) UnionWith(other Set) {
+	ints, complete := intsOf(other)
+	if !complete {
+		panic(fmt.Sprintf("IntSet only accepts non-negative integers, got %v", other))
+	}
+	set.combineWith(ints, opOr)
+}
+
+func (set *IntSet) IntersectWith(other Set) {
+	ints, _ := intsOf(other)
+	set.combineWith(ints, opAnd)
+}
+
+func (set *IntSet) DifferenceWith(other Set) {
+	ints, _ := intsOf(other)
+	set.combineWith(ints, opAndNot)
+}
+
+// SymmetricDifferenceWith panics if the other set contains no non-negative integers.
+func (set *IntSet) SymmetricDifferenceWith(other Set) {
+	ints, complete := intsOf(other)
+	if !complete {
+		panic(fmt.Sprintf("IntSet only accepts non-negative integers, got %v", other))
+	}
+	set.combineWith(ints, opXor)
+}
+
+// similarity determines the cardinalities of the intersection and of both sets.
+func (set *IntSet) similarity(other Set) (intersection, a, b int) {
+	o, ok := other.(*IntSet)
+	if !ok {
+		o, _ = intsOf(other)
+		b = other.Cardinality()
+	}
+	unlock := lockInts(set, o)
+	defer unlock()
+	if ok {
+		b = o.cardinality()
+	}
+	return combine(set, o, opAnd, false).cardinality(), set.cardinality(), b
+}
+
+func (set *IntSet) Jaccard(other Set) float64 {
+	intersection, a, b := set.similarity(other)
+	if a == 0 && b == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(a+b-intersection)
+}
+
+func (set *IntSet) Overlap(other Set) float64 {
+	intersection, a, b := set.similarity(other)
+	if a == 0 || b == 0 {
+		if a == b {
+			return 1
+		}
+		return 0
+	}
+	if b < a {
+		a = b
+	}
+	return float64(intersection) / float64(a)
+}
+
+func (set *IntSet) Dice(other Set) float64 {
+	intersection, a, b := set.similarity(other)
+	if a == 0 && b == 0 {
+		return 1
+	}
+	return 2 * float64(intersection) / float64(a+b)
+}
+
+func (set *IntSet) Clear() {
+	set.Lock()
+	defer set.Unlock()
+	set.chunks = nil
+	set.cache = nil
+}
+
+func (set *IntSet) Clone() Set {
+	set.RLock()
+	defer set.RUnlock()
+	return set.clone()
+}
+
+func (set *IntSet) Contains(i ...interface{}) bool {
+	set.RLock()
+	defer set.RUnlock()
+	for _, val := range i {
+		if v, ok := toUint64(val); !ok || !set.contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *IntSet) Difference(other Set) Set {
+	ints, _ := intsOf(other)
+	return set.combined(ints, opAndNot)
+}
+
+func (set *IntSet) Intersect(other Set) Set {
+	ints, _ := intsOf(other)
+	return set.combined(ints, opAnd)
+}
+
+// Union provides an IntSet if the other set only contains non-negative integers and a generic set otherwise.
+func (set *IntSet) Union(other Set) Set {
+	ints, complete := intsOf(other)
+	if !complete {
+		return set.snapshot().Union(other)
+	}
+	return set.combined(ints, opOr)
+}
+
+// SymmetricDifference provides an IntSet if the other set only contains non-negative integers
+// and a generic set otherwise.
+func (set *IntSet) SymmetricDifference(other Set) Set {
+	ints, complete := intsOf(other)
+	if !complete {
+		return set.snapshot().SymmetricDifference(other)
+	}
+	return set.combined(ints, opXor)
+}
+
+func (set *IntSet) Equal(other Set) bool {
+	o, ok := other.(*IntSet)
+	if !ok {
+		return set.snapshot().Equal(other)
+	}
+	unlock := lockInts(set, o)
+	defer unlock()
+	return set.cardinality() == o.cardinality() && len(combine(set, o, opXor, false).chunks) == 0
+}
+
+func (set *IntSet) IsSubset(other Set) bool {
+	o, ok := other.(*IntSet)
+	if !ok {
+		return set.snapshot().IsSubset(other)
+	}
+	unlock := lockInts(set, o)
+	defer unlock()
+	return len(combine(set, o, opAndNot, false).chunks) == 0
+}
+
+func (set *IntSet) IsProperSubset(other Set) bool {
+	return set.Cardinality() < other.Cardinality() && set.IsSubset(other)
+}
+
+func (set *IntSet) IsSuperset(other Set) bool {
+	if o, ok := other.(*IntSet); ok {
+		return o.IsSubset(set)
+	}
+	return set.snapshot().IsSuperset(other)
+}
+
+func (set *IntSet) IsProperSuperset(other Set) bool {
+	return set.Cardinality() > other.Cardinality() && set.IsSuperset(other)
+}
+
+// Each iterates over the elements in ascending order.
+func (set *IntSet) Each(cb func(interface{}) bool) {
+	set.RLock()
+	defer set.RUnlock()
+	set.each(func(v uint64) bool {
+		return cb(v)
+	})
+}
+
+func (set *IntSet) Filter(predicate func(interface{}) bool) Set {
+	set.RLock()
+	defer set.RUnlock()
+	filtered := &IntSet{}
+	set.each(func(v uint64) bool {
+		if predicate(v) {
+			filtered.add(v)
+		}
+		return false
+	})
+	return filtered
+}
+
+// Map provides a generic set since the mapped elements may be no integers.
+func (set *IntSet) Map(mapper func(interface{}) interface{}) Set {
+	return set.snapshot().Map(mapper)
+}
+
+func (set *IntSet) Partition(predicate func(interface{}) bool) (matching, rest Set) {
+	set.RLock()
+	defer set.RUnlock()
+	m, r := &IntSet{}, &IntSet{}
+	set.each(func(v uint64) bool {
+		if predicate(v) {
+			m.add(v)
+		} else {
+			r.add(v)
+		}
+		return false
+	})
+	return m, r
+}
+
+func (set *IntSet) GroupBy(key func(interface{}) interface{}) map[interface{}]Set {
+	set.RLock()
+	defer set.RUnlock()
+	groups := make(map[interface{}]Set)
+	set.each(func(v uint64) bool {
+		k := key(v)
+		group, ok := groups[k]
+		if !ok {
+			group = &IntSet{}
+			groups[k] = group
+		}
+		group.(*IntSet).add(v)
+		return false
+	})
+	return groups
+}
+
+func (set *IntSet) Reduce(reducer func(accumulator, elem interface{}) interface{}, initial interface{}) interface{} {
+	accumulator := initial
+	set.Each(func(elem interface{}) bool {
+		accumulator = reducer(accumulator, elem)
+		return false
+	})
+	return accumulator
+}
+
+func (set *IntSet) Any(predicate func(interface{}) bool) (found bool) {
+	set.Each(func(elem interface{}) bool {
+		found = predicate(elem)
+		return found
+	})
+	return
+}
+
+func (set *IntSet) All(predicate func(interface{}) bool) bool {
+	return !set.Any(func(elem interface{}) bool {
+		return !predicate(elem)
+	})
+}
+
+func (set *IntSet) Count(predicate func(interface{}) bool) (count int) {
+	set.Each(func(elem interface{}) bool {
+		if predicate(elem) {
+			count++
+		}
+		return false
+	})
+	return
+}
+
+// Iter iterates over a snapshot of the elements in ascending order.
+func (set *IntSet) Iter() <-chan interface{} {
+	ch := make(chan interface{})
+	elements := set.ToSlice()
+	go func() {
+		for _, elem := range elements {
+			ch <- elem
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Iterator iterates over a snapshot of the elements in ascending order.
+func (set *IntSet) Iterator() *Iterator {
+	iterator, ch, stopCh := newIterator()
+	elements := set.ToSlice()
+
+	go func() {
+	L:
+		for _, elem := range elements {
+			select {
+			case <-stopCh:
+				break L
+			case ch <- elem:
+			}
+		}
+		close(ch)
+	}()
+
+	return iterator
+}
+
+func (set *IntSet) Remove(i ...interface{}) {
+	set.Lock()
+	defer set.Unlock()
+	for _, val := range i {
+		if v, ok := toUint64(val); ok {
+			set.remove(v)
+		}
+	}
+	set.cache = nil
+}
+
+// String provides the elements in ascending order.
+func (set *IntSet) String() string {
+	set.RLock()
+	defer set.RUnlock()
+	if len(set.chunks) == 0 {
+		return "Set{}"
+	}
+	items := bytes.NewBufferString("Set{")
+	set.each(func(v uint64) bool {
+		_, err := fmt.Fprintf(items, "%v, ", v)
+		if err != nil {
+			panic(err)
+		}
+		return false
+	})
+	items.Truncate(items.Len() - 2)
+	items.WriteString("}")
+	return items.String()
+}
+
+// Pop removes and returns the smallest element.
+func (set *IntSet) Pop() interface{} {
+	set.Lock()
+	defer set.Unlock()
+	var popped interface{}
+	set.each(func(v uint64) bool {
+		popped = v
+		return true
+	})
+	if popped != nil {
+		set.remove(popped.(uint64))
+		set.cache = nil
+	}
+	return popped
+}
+
+func (set *IntSet) PowerSet() Set {
+	return set.snapshot().PowerSet()
+}
+
+func (set *IntSet) CartesianProduct(other Set) Set {
+	return set.snapshot().CartesianProduct(other)
+}
+
+func (set *IntSet) Sketch(precision uint8) *Sketch {
+	return set.snapshot().Sketch(precision)
+}
+
+// ToSlice provides the elements in ascending order.
+func (set *IntSet) ToSlice() []interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	keys := make([]interface{}, 0, set.cardinality())
+	set.each(func(v uint64) bool {
+		keys = append(keys, v)
+		return false
+	})
+	return keys
+}
+
+func (set *IntSet) CoreSet() threadUnsafeSet {
+	return set.snapshot().threadUnsafeSet
+}
+
+func (set *IntSet) ThreadSafe() *threadSafeSet {
+	return set.snapshot()
+}
+
+// MarshalJSON provides the elements as JSON array in ascending order.
+func (set *IntSet) MarshalJSON() ([]byte, error) {
+	set.RLock()
+	defer set.RUnlock()
+	values := make([]uint64, 0, set.cardinality())
+	set.each(func(v uint64) bool {
+		values = append(values, v)
+		return false
+	})
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON adds the non-negative integers of a JSON array.
+func (set *IntSet) UnmarshalJSON(p []byte) error {
+	var values []uint64
+	if err := json.Unmarshal(p, &values); err != nil {
+		return err
+	}
+	set.Lock()
+	defer set.Unlock()
+	for _, v := range values {
+		set.add(v)
+	}
+	set.cache = nil
+	return nil
+}
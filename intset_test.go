@@ -0,0 +1,169 @@
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// randomInts creates an IntSet and an equal generic set of random integers,
+// with a sparse chunk, a dense chunk, and a chunk of consecutive values.
+func randomInts(r *rand.Rand) (*IntSet, Set) {
+	ints, generic := NewIntSet(), NewSet()
+	add := func(v uint64) {
+		ints.Add(v)
+		generic.Add(v)
+	}
+	for n := 0; n < 100; n++ {
+		add(uint64(r.Intn(1 << 16)))
+	}
+	for n := 0; n < 6000; n++ {
+		add(1<<16 + uint64(r.Intn(8000)))
+	}
+	start := 2<<16 + uint64(r.Intn(1000))
+	for v := start; v < start+uint64(r.Intn(5000)); v++ {
+		add(v)
+	}
+	add(1 << 40)
+	return ints, generic
+}
+
+func Test_IntSetMatchesGenericSet(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for round := 0; round < 5; round++ {
+		a, genericA := randomInts(r)
+		b, genericB := randomInts(r)
+		if round%2 == 1 {
+			a.RunOptimize()
+		}
+
+		if a.Cardinality() != genericA.Cardinality() || !a.Equal(genericA) || !genericA.Equal(a) {
+			t.Fatal("IntSet should equal the generic set of the same integers")
+		}
+		if a.Hash() != genericA.Hash() {
+			t.Fatal("IntSet should hash like the generic set of the same integers")
+		}
+
+		results := []struct {
+			name    string
+			ints    Set
+			generic Set
+		}{
+			{"union", a.Union(b), genericA.Union(genericB)},
+			{"intersect", a.Intersect(b), genericA.Intersect(genericB)},
+			{"difference", a.Difference(b), genericA.Difference(genericB)},
+			{"symmetric difference", a.SymmetricDifference(b), genericA.SymmetricDifference(genericB)},
+			{"mixed intersect", a.Intersect(genericB), genericA.Intersect(genericB)},
+			{"mixed difference", genericA.Difference(b), genericA.Difference(genericB)},
+		}
+		for _, res := range results {
+			if _, ok := res.ints.(*IntSet); !ok && res.name[:5] != "mixed" {
+				t.Errorf("%s of IntSets should be an IntSet", res.name)
+			}
+			if !res.ints.Equal(res.generic) {
+				t.Errorf("%s differs from the generic result", res.name)
+			}
+		}
+
+		if a.Jaccard(b) != genericA.Jaccard(genericB) || a.Dice(genericB) != genericA.Dice(genericB) {
+			t.Error("similarities should match the generic results")
+		}
+		if !a.Intersect(b).IsSubset(a) || !a.IsSuperset(a.Intersect(b)) || !genericA.IsSubset(a.Union(b)) {
+			t.Error("subset relations should hold")
+		}
+	}
+}
+
+func Test_IntSetInPlace(t *testing.T) {
+	set := NewIntSet(1, 2, 3)
+	set.UnionWith(NewSet(3, 4, int64(5)))
+	set.IntersectWith(NewIntSet(2, 3, 4, 5, 6))
+	set.DifferenceWith(NewSet("foo", 3))
+	set.SymmetricDifferenceWith(NewIntSet(5, 7))
+
+	if !set.Equal(NewSet(2, 4, 7)) {
+		t.Errorf("expected {2, 4, 7}, got %v", set)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("adding a non-integer should panic")
+		}
+	}()
+	set.Add(-1)
+}
+
+func Test_IntSetMixed(t *testing.T) {
+	set := NewIntSet(1, 2)
+	mixed := set.Union(NewSet("foo"))
+	if _, ok := mixed.(*IntSet); ok || !mixed.Equal(NewSet(1, 2, "foo")) {
+		t.Errorf("union with non-integers should be a generic set, got %v", mixed)
+	}
+	if !set.Contains(1, int64(2), uint(2)) || set.Contains("foo") || set.Contains(-1) {
+		t.Error("Contains should accept integer types that hash like uint64")
+	}
+	if set.Contains(int32(2)) || set.Contains(uint8(2)) {
+		t.Error("Contains should reject integer types whose hashes differ, like a generic set")
+	}
+	narrow := set.Union(NewSet(int32(7)))
+	if _, ok := narrow.(*IntSet); ok || !narrow.Contains(int32(7)) || narrow.Contains(uint64(7)) {
+		t.Errorf("union with narrow integers should keep their type, got %v", narrow)
+	}
+	if set.Equal(NewSet(int32(1), int32(2))) || NewSet(int32(1), int32(2)).Equal(set) {
+		t.Error("IntSet should not equal a generic set of narrow integers")
+	}
+	if !NewSet(NewSet(1, 2)).Contains(set) {
+		t.Error("IntSet should be found in a set of sets")
+	}
+}
+
+func Test_IntSetOrder(t *testing.T) {
+	set := NewIntSet(1<<20, 3, 1<<16, 1)
+	if s := set.String(); s != "Set{1, 3, 65536, 1048576}" {
+		t.Errorf("unexpected string %s", s)
+	}
+	if min, _ := set.Min(); min != 1 {
+		t.Errorf("expected min 1, got %d", min)
+	}
+	if max, _ := set.Max(); max != 1<<20 {
+		t.Errorf("expected max 1048576, got %d", max)
+	}
+	if popped := set.Pop(); popped != uint64(1) {
+		t.Errorf("expected 1 to be popped, got %v", popped)
+	}
+
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "[3,65536,1048576]" {
+		t.Errorf("unexpected JSON %s", b)
+	}
+	decoded := NewIntSet()
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(set) {
+		t.Errorf("expected %v, got %v", set, decoded)
+	}
+}
+
+func Test_IntSetContainers(t *testing.T) {
+	set := NewIntSet()
+	for v := uint64(0); v < 10000; v++ {
+		set.Add(v)
+	}
+	set.Add(1<<16 + 7)
+	if arrays, bitmaps, runs := set.Containers(); arrays != 1 || bitmaps != 1 || runs != 0 {
+		t.Errorf("expected an array and a bitmap, got %d, %d, %d", arrays, bitmaps, runs)
+	}
+
+	set.RunOptimize()
+	if arrays, bitmaps, runs := set.Containers(); arrays != 1 || bitmaps != 0 || runs != 1 {
+		t.Errorf("expected an array and a run, got %d, %d, %d", arrays, bitmaps, runs)
+	}
+	set.Remove(uint64(5000))
+	if set.Cardinality() != 10000 || set.Contains(5000) || !set.Contains(4999, 5001) {
+		t.Errorf("removal from a run container failed, got %d elements", set.Cardinality())
+	}
+}
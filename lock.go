@@ -0,0 +1,55 @@
+package mapset
+
+import (
+	"reflect"
+	"sort"
+)
+
+// lockSets locks the given writer and read-locks the given readers once each in a consistent order,
+// which prevents deadlocks between concurrent operations on the same sets.
+// The writer may be nil. Readers that are the writer or that occur multiple times are only locked once.
+// The returned function releases all locks.
+func lockSets(writer *threadSafeSet, readers ...*threadSafeSet) (unlock func()) {
+	unique := make(map[*threadSafeSet]bool, len(readers)+1)
+	for _, reader := range readers {
+		unique[reader] = false
+	}
+	if writer != nil {
+		unique[writer] = true
+	}
+	ordered := make([]*threadSafeSet, 0, len(unique))
+	for set := range unique {
+		ordered = append(ordered, set)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return reflect.ValueOf(ordered[i]).Pointer() < reflect.ValueOf(ordered[j]).Pointer()
+	})
+
+	for _, set := range ordered {
+		if unique[set] {
+			set.Lock()
+		} else {
+			set.RLock()
+		}
+	}
+	return func() {
+		for n := len(ordered) - 1; n >= 0; n-- {
+			if unique[ordered[n]] {
+				ordered[n].Unlock()
+			} else {
+				ordered[n].RUnlock()
+			}
+		}
+	}
+}
+
+// readLockAll read-locks the given sets and provides their cores.
+func readLockAll(sets []Set) (cores []*threadUnsafeSet, unlock func()) {
+	safe := make([]*threadSafeSet, len(sets))
+	cores = make([]*threadUnsafeSet, len(sets))
+	for n, set := range sets {
+		safe[n] = set.ThreadSafe()
+		cores[n] = &safe[n].threadUnsafeSet
+	}
+	return cores, lockSets(nil, safe...)
+}
@@ -0,0 +1,247 @@
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MaxMerkleDepth is the deepest Merkle index that can be built, i.e., the number of hash prefix bits used for the leaves.
+const MaxMerkleDepth = 24
+
+// ErrMerkleDepth is returned if two Merkle indexes with different depths are reconciled.
+var ErrMerkleDepth = errors.New("merkle indexes have different depths")
+
+// ErrMerkleResponse is returned if a remote Merkle index replies with a different number of digests than requested.
+var ErrMerkleResponse = errors.New("merkle transport returned an unexpected number of digests")
+
+// MerkleIndex is a snapshot of the element hashes of a set, bucketed by their hash prefix into a binary tree of digests.
+// While the hash of a set only tells two replicas that they differ, comparing the digests of two indexes level by level
+// narrows the difference down to the leaf buckets that contain it.
+type MerkleIndex struct {
+	depth    int
+	levels   [][]uint64
+	buckets  [][]uint64
+	elements map[uint64]interface{}
+	template *threadUnsafeSet
+	set      Set
+}
+
+// MerkleTransport exchanges the digests and elements of a remote Merkle index.
+type MerkleTransport interface {
+	// Depth returns the depth of the remote index.
+	Depth() int
+
+	// Digests returns the remote digests of the given nodes on the given level.
+	Digests(level int, nodes []int) ([]uint64, error)
+
+	// Hashes returns the remote element hashes that are stored in the given leaf buckets.
+	Hashes(leaves []int) ([]uint64, error)
+
+	// Elements returns the remote elements for the given element hashes.
+	Elements(hashes []uint64) ([]interface{}, error)
+}
+
+// NewMerkleIndex builds a Merkle index with the given depth over the current elements of the given set.
+// The index contains 2^depth leaf buckets. It panics if the depth is not in the range of 0 to MaxMerkleDepth.
+// The index is not updated if the set is modified afterwards.
+func NewMerkleIndex(set Set, depth int) *MerkleIndex {
+	if depth < 0 || depth > MaxMerkleDepth {
+		panic(fmt.Sprintf("merkle depth %d is out of range [0, %d]", depth, MaxMerkleDepth))
+	}
+	o := set.ThreadSafe()
+	o.RLock()
+	defer o.RUnlock()
+
+	index := &MerkleIndex{
+		depth:    depth,
+		levels:   make([][]uint64, depth+1),
+		buckets:  make([][]uint64, 1<<uint(depth)),
+		elements: make(map[uint64]interface{}, o.threadUnsafeSet.Cardinality()),
+		template: o.threadUnsafeSet.derived(0),
+		set:      set,
+	}
+	for l := range index.levels {
+		index.levels[l] = make([]uint64, 1<<uint(l))
+	}
+	leaves := index.levels[depth]
+	for h, elem := range o.threadUnsafeSet.anyMap {
+		index.elements[h] = elem
+		leaf := index.leafFor(h)
+		index.buckets[leaf] = append(index.buckets[leaf], h)
+		leaves[leaf] += mix64(h)
+	}
+	for l := depth - 1; l >= 0; l-- {
+		for n := range index.levels[l] {
+			index.levels[l][n] = index.levels[l+1][2*n] + index.levels[l+1][2*n+1]
+		}
+	}
+	return index
+}
+
+// Depth returns the depth of the index.
+func (index *MerkleIndex) Depth() int {
+	return index.depth
+}
+
+// Digest returns the root digest of the index. Two indexes over equal sets have the same root digest.
+func (index *MerkleIndex) Digest() uint64 {
+	return index.levels[0][0]
+}
+
+// Digests returns the digests of the given nodes on the given level.
+func (index *MerkleIndex) Digests(level int, nodes []int) ([]uint64, error) {
+	if level < 0 || level > index.depth {
+		return nil, fmt.Errorf("merkle level %d is out of range [0, %d]", level, index.depth)
+	}
+	digests := make([]uint64, 0, len(nodes))
+	for _, n := range nodes {
+		if n < 0 || n >= len(index.levels[level]) {
+			return nil, fmt.Errorf("merkle node %d does not exist on level %d", n, level)
+		}
+		digests = append(digests, index.levels[level][n])
+	}
+	return digests, nil
+}
+
+// Hashes returns the element hashes that are stored in the given leaf buckets.
+func (index *MerkleIndex) Hashes(leaves []int) ([]uint64, error) {
+	var hashes []uint64
+	for _, leaf := range leaves {
+		if leaf < 0 || leaf >= len(index.buckets) {
+			return nil, fmt.Errorf("merkle leaf %d does not exist", leaf)
+		}
+		hashes = append(hashes, index.buckets[leaf]...)
+	}
+	return hashes, nil
+}
+
+// Elements returns the elements for the given element hashes. Unknown hashes are skipped.
+func (index *MerkleIndex) Elements(hashes []uint64) ([]interface{}, error) {
+	elements := make([]interface{}, 0, len(hashes))
+	for _, h := range hashes {
+		if elem, ok := index.elements[h]; ok {
+			elements = append(elements, elem)
+		}
+	}
+	return elements, nil
+}
+
+// Reconcile compares this index with the remote index that is reachable through the given transport.
+// It descends only into the subtrees whose digests differ and transfers only the elements that are missing locally.
+// The returned missing set contains all elements that only exist remotely,
+// the surplus set contains all elements that only exist locally.
+// Both sets use the same implementation and options as the indexed set.
+func (index *MerkleIndex) Reconcile(remote MerkleTransport) (missing, surplus Set, err error) {
+	if remote.Depth() != index.depth {
+		return nil, nil, ErrMerkleDepth
+	}
+	missingSet := index.template.derived(0)
+	surplusSet := index.template.derived(0)
+
+	nodes := []int{0}
+	for level := 0; level <= index.depth && len(nodes) > 0; level++ {
+		digests, err := remote.Digests(level, nodes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(digests) != len(nodes) {
+			return nil, nil, ErrMerkleResponse
+		}
+		var differing []int
+		for i, n := range nodes {
+			if digests[i] != index.levels[level][n] {
+				differing = append(differing, n)
+			}
+		}
+		if level == index.depth {
+			nodes = differing
+			break
+		}
+		nodes = nodes[:0]
+		for _, n := range differing {
+			nodes = append(nodes, 2*n, 2*n+1)
+		}
+	}
+
+	if len(nodes) > 0 {
+		remoteHashes, err := remote.Hashes(nodes)
+		if err != nil {
+			return nil, nil, err
+		}
+		remoteSet := make(map[uint64]struct{}, len(remoteHashes))
+		var wanted []uint64
+		for _, h := range remoteHashes {
+			remoteSet[h] = struct{}{}
+			if _, ok := index.elements[h]; !ok {
+				wanted = append(wanted, h)
+			}
+		}
+		for _, leaf := range nodes {
+			for _, h := range index.buckets[leaf] {
+				if _, ok := remoteSet[h]; !ok {
+					surplusSet.addWithHash(index.elements[h], h)
+				}
+			}
+		}
+		if len(wanted) > 0 {
+			sort.Slice(wanted, func(i, j int) bool { return wanted[i] < wanted[j] })
+			elements, err := remote.Elements(wanted)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, elem := range elements {
+				missingSet.addWithHash(elem, missingSet.hashFor(elem))
+			}
+		}
+	}
+
+	return resultLike(index.set, missingSet), resultLike(index.set, surplusSet), nil
+}
+
+func (index *MerkleIndex) leafFor(h uint64) int {
+	if index.depth == 0 {
+		return 0
+	}
+	return int(mix64(h) >> uint(64-index.depth))
+}
+
+// InProcessTransport is a MerkleTransport that accesses a Merkle index in the same process.
+// It counts the exchanged messages, which makes it suitable to test reconciliations.
+type InProcessTransport struct {
+	// Index is the remote index.
+	Index *MerkleIndex
+	// RoundTrips is the number of requests that have been sent to the index.
+	RoundTrips int
+	// Transferred is the number of digests, hashes, and elements that have been received from the index.
+	Transferred int
+}
+
+// Depth returns the depth of the remote index.
+func (t *InProcessTransport) Depth() int {
+	return t.Index.Depth()
+}
+
+// Digests returns the remote digests of the given nodes on the given level.
+func (t *InProcessTransport) Digests(level int, nodes []int) ([]uint64, error) {
+	t.RoundTrips++
+	digests, err := t.Index.Digests(level, nodes)
+	t.Transferred += len(digests)
+	return digests, err
+}
+
+// Hashes returns the remote element hashes that are stored in the given leaf buckets.
+func (t *InProcessTransport) Hashes(leaves []int) ([]uint64, error) {
+	t.RoundTrips++
+	hashes, err := t.Index.Hashes(leaves)
+	t.Transferred += len(hashes)
+	return hashes, err
+}
+
+// Elements returns the remote elements for the given element hashes.
+func (t *InProcessTransport) Elements(hashes []uint64) ([]interface{}, error) {
+	t.RoundTrips++
+	elements, err := t.Index.Elements(hashes)
+	t.Transferred += len(elements)
+	return elements, err
+}
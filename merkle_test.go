@@ -0,0 +1,141 @@
+package mapset
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_MerkleIndexEqualSets(t *testing.T) {
+	a := makeSet([]int{1, 2, 3, 4, 5})
+	b := makeUnsafeSet([]int{5, 4, 3, 2, 1})
+
+	ia := NewMerkleIndex(a, 8)
+	ib := NewMerkleIndex(b, 8)
+
+	if ia.Digest() != ib.Digest() {
+		t.Error("indexes of equal sets should have the same root digest")
+	}
+
+	transport := &InProcessTransport{Index: ib}
+	missing, surplus, err := ia.Reconcile(transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !missing.Empty() || !surplus.Empty() {
+		t.Errorf("equal sets should not differ, missing %v, surplus %v", missing, surplus)
+	}
+	if transport.RoundTrips != 1 {
+		t.Errorf("equal sets should only compare the root digest, but sent %d requests", transport.RoundTrips)
+	}
+}
+
+func Test_MerkleIndexReconcile(t *testing.T) {
+	const size = 10000
+	a := NewSet()
+	b := NewSet()
+	for i := 0; i < size; i++ {
+		a.Add(i)
+		b.Add(i)
+	}
+	a.Add("only a", 1.5)
+	b.Add("only b")
+	b.Remove(42)
+
+	ia := NewMerkleIndex(a, 12)
+	transport := &InProcessTransport{Index: NewMerkleIndex(b, 12)}
+	missing, surplus, err := ia.Reconcile(transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(missing, NewSet("only b"), t)
+	assertEqual(surplus, NewSet("only a", 1.5, 42), t)
+
+	if _, ok := missing.(*threadSafeSet); !ok {
+		t.Error("reconciliation results of a thread-safe set should be thread-safe")
+	}
+	if transport.Transferred >= size {
+		t.Errorf("reconciliation should transfer less than the whole set, but transferred %d items", transport.Transferred)
+	}
+
+	if !a.Union(missing).Difference(surplus).Equal(b) {
+		t.Error("applying the differences should result in the remote set")
+	}
+}
+
+func Test_MerkleIndexEmpty(t *testing.T) {
+	a := NewSet()
+	b := NewSet("one", "two")
+
+	missing, surplus, err := NewMerkleIndex(a, 0).Reconcile(&InProcessTransport{Index: NewMerkleIndex(b, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(missing, b, t)
+	assertEqual(surplus, NewSet(), t)
+}
+
+func Test_MerkleIndexDepthMismatch(t *testing.T) {
+	a := NewMerkleIndex(NewSet(1), 4)
+	b := NewMerkleIndex(NewSet(1), 5)
+
+	if _, _, err := a.Reconcile(&InProcessTransport{Index: b}); err != ErrMerkleDepth {
+		t.Errorf("expected depth mismatch error, got %v", err)
+	}
+}
+
+// shortTransport drops the last digest of every reply.
+type shortTransport struct {
+	*InProcessTransport
+}
+
+func (t shortTransport) Digests(level int, nodes []int) ([]uint64, error) {
+	digests, err := t.InProcessTransport.Digests(level, nodes)
+	return digests[:len(digests)-1], err
+}
+
+func Test_MerkleIndexShortResponse(t *testing.T) {
+	a := NewMerkleIndex(NewSet(1), 4)
+	b := NewMerkleIndex(NewSet(2), 4)
+
+	if _, _, err := a.Reconcile(shortTransport{&InProcessTransport{Index: b}}); err != ErrMerkleResponse {
+		t.Errorf("expected response error, got %v", err)
+	}
+}
+
+func Test_MerkleIndexDepthOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("an out of range depth should panic")
+		}
+	}()
+	NewMerkleIndex(NewSet(), MaxMerkleDepth+1)
+}
+
+func Test_MerkleIndexResultImplementation(t *testing.T) {
+	remote := NewMerkleIndex(NewSet(1, 2, 3), 4)
+	tests := []struct {
+		name string
+		set  Set
+		safe bool
+	}{
+		{name: "safe", set: NewSet(1), safe: true},
+		{name: "unsafe", set: NewUnsafeSet(1), safe: false},
+		{name: "expiring", set: NewExpiringSet(time.Hour, 1), safe: true},
+		{name: "bounded", set: NewBoundedSet(10, LRUEviction, 1), safe: true},
+		{name: "integers", set: NewIntSet(1), safe: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, surplus, err := NewMerkleIndex(tt.set, 4).Reconcile(&InProcessTransport{Index: remote})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, result := range []Set{missing, surplus} {
+				if _, safe := result.(*threadSafeSet); safe != tt.safe {
+					t.Errorf("reconciled set %v should be thread-safe: %v", result, tt.safe)
+				}
+			}
+		})
+	}
+}
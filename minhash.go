@@ -0,0 +1,138 @@
+package mapset
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Signature is a MinHash signature of a set.
+// The fraction of equal slots of two signatures estimates the Jaccard similarity of their sets.
+type Signature []uint64
+
+// MinHash builds a MinHash signature of the given size from the element hashes of the given set.
+// Every slot holds the minimum of a differently seeded permutation of the element hashes.
+// Signatures are only comparable if their sets use the same hash function.
+func MinHash(set Set, size int) Signature {
+	o := set.ThreadSafe()
+	o.RLock()
+	defer o.RUnlock()
+
+	signature := make(Signature, size)
+	for n := range signature {
+		signature[n] = ^uint64(0)
+	}
+	for h := range o.threadUnsafeSet.anyMap {
+		for n := range signature {
+			if v := mix64(h ^ minHashSeed(n)); v < signature[n] {
+				signature[n] = v
+			}
+		}
+	}
+	return signature
+}
+
+// Similarity estimates the Jaccard similarity of the sets of this signature and the given signature.
+// It panics if the signatures have different sizes.
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) != len(other) {
+		panic(fmt.Sprintf("signatures have different sizes %d and %d", len(s), len(other)))
+	}
+	if len(s) == 0 {
+		return 1
+	}
+	var equal int
+	for n := range s {
+		if s[n] == other[n] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s))
+}
+
+func minHashSeed(n int) uint64 {
+	return mix64(uint64(n) + multisetSeed)
+}
+
+// LSHIndex finds candidates of similar sets among a large collection by locality-sensitive hashing.
+// It splits the MinHash signatures into bands of rows and considers two sets candidates
+// if all rows of at least one band are equal.
+// Given b bands of r rows, sets with a Jaccard similarity s become candidates with a probability of 1-(1-s^r)^b.
+// Operations on an LSHIndex are thread-safe.
+type LSHIndex struct {
+	sync.RWMutex
+	bands   int
+	rows    int
+	buckets []map[uint64][]interface{}
+}
+
+// NewLSHIndex creates an empty index for signatures of bands*rows slots.
+func NewLSHIndex(bands, rows int) *LSHIndex {
+	if bands < 1 || rows < 1 {
+		panic(fmt.Sprintf("lsh index needs at least one band and row, got %d bands of %d rows", bands, rows))
+	}
+	buckets := make([]map[uint64][]interface{}, bands)
+	for b := range buckets {
+		buckets[b] = make(map[uint64][]interface{})
+	}
+	return &LSHIndex{
+		bands:   bands,
+		rows:    rows,
+		buckets: buckets,
+	}
+}
+
+// Insert adds the given key with its signature to the index.
+// It panics if the signature does not have bands*rows slots.
+func (index *LSHIndex) Insert(key interface{}, signature Signature) {
+	index.Lock()
+	defer index.Unlock()
+	for b, h := range index.bandHashes(signature) {
+		index.buckets[b][h] = append(index.buckets[b][h], key)
+	}
+}
+
+// Query provides the keys of all candidates that share at least one band with the given signature.
+// It panics if the signature does not have bands*rows slots.
+func (index *LSHIndex) Query(signature Signature) Set {
+	index.RLock()
+	defer index.RUnlock()
+	candidates := NewSet()
+	for b, h := range index.bandHashes(signature) {
+		candidates.Add(index.buckets[b][h]...)
+	}
+	return candidates
+}
+
+// CandidatePairs provides the pairs of keys that share at least one band as a set of OrderedPair.
+// The first key of every pair was inserted before the second one.
+func (index *LSHIndex) CandidatePairs() Set {
+	index.RLock()
+	defer index.RUnlock()
+	pairs := NewSet()
+	for _, bucket := range index.buckets {
+		for _, keys := range bucket {
+			for i := range keys {
+				for j := i + 1; j < len(keys); j++ {
+					pairs.Add(OrderedPair{First: keys[i], Second: keys[j]})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+func (index *LSHIndex) bandHashes(signature Signature) []uint64 {
+	if len(signature) != index.bands*index.rows {
+		panic(fmt.Sprintf("signature has %d slots, but the index expects %d bands of %d rows",
+			len(signature), index.bands, index.rows))
+	}
+	hashes := make([]uint64, index.bands)
+	for b := range hashes {
+		var h uint64
+		for _, v := range signature[b*index.rows : (b+1)*index.rows] {
+			h = mix64(h ^ v)
+		}
+		hashes[b] = h
+	}
+	return hashes
+}
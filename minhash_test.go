@@ -0,0 +1,51 @@
+package mapset
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_MinHashSimilarity(t *testing.T) {
+	a := NewSet()
+	b := NewUnsafeSet()
+	for i := 0; i < 1000; i++ {
+		a.Add(i)
+		b.Add(i + 500)
+	}
+
+	similarity := MinHash(a, 256).Similarity(MinHash(b, 256))
+	if exact := a.Jaccard(b); math.Abs(similarity-exact) > 0.1 {
+		t.Errorf("estimated similarity %v is too far from %v", similarity, exact)
+	}
+	if MinHash(a, 64).Similarity(MinHash(a.Clone(), 64)) != 1 {
+		t.Error("signatures of equal sets should be equal")
+	}
+}
+
+func Test_LSHIndex(t *testing.T) {
+	tags := map[string]Set{
+		"a": NewSet("go", "sets", "hashing", "xxhash", "library", "generic", "fast", "maps"),
+		"b": NewSet("go", "sets", "hashing", "xxhash", "library", "generic", "fast", "slices"),
+		"c": NewSet("python", "pandas", "numpy", "dataframes", "csv"),
+	}
+
+	index := NewLSHIndex(32, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		index.Insert(key, MinHash(tags[key], 64))
+	}
+
+	candidates := index.Query(MinHash(tags["a"], 64))
+	if !candidates.Contains("a", "b") || candidates.Contains("c") {
+		t.Errorf("unexpected candidates %v", candidates)
+	}
+	assertEqual(index.CandidatePairs(), NewSet(OrderedPair{First: "a", Second: "b"}), t)
+}
+
+func Test_LSHIndexSignatureSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("a signature of the wrong size should panic")
+		}
+	}()
+	NewLSHIndex(4, 4).Insert("a", MinHash(NewSet(1), 15))
+}
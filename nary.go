@@ -0,0 +1,106 @@
+package mapset
+
+import (
+	"sort"
+)
+
+// UnionAll provides a new set with all elements of the given sets.
+// All sets are locked once, and the result is allocated a single time.
+// The result uses the same implementation and options as the first set. Without sets, it is an empty thread-safe set.
+func UnionAll(sets ...Set) Set {
+	if len(sets) == 0 {
+		return NewSet()
+	}
+	cores, unlock := readLockAll(sets)
+	defer unlock()
+
+	var size int
+	for _, core := range cores {
+		size += core.Cardinality()
+	}
+	union := cores[0].derived(size)
+	for _, core := range cores {
+		for h, elem := range core.anyMap {
+			union.addWithHash(elem, h)
+		}
+	}
+	return resultLike(sets[0], union)
+}
+
+// IntersectAll provides a new set with the elements that exist in all given sets.
+// The sets are evaluated from the smallest to the largest one. All sets are locked once,
+// and the result is allocated a single time.
+// The result uses the same implementation and options as the first set. Without sets, it is an empty thread-safe set.
+func IntersectAll(sets ...Set) Set {
+	if len(sets) == 0 {
+		return NewSet()
+	}
+	cores, unlock := readLockAll(sets)
+	defer unlock()
+
+	ordered := append([]*threadUnsafeSet(nil), cores...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Cardinality() < ordered[j].Cardinality()
+	})
+	intersection := cores[0].derived(ordered[0].Cardinality())
+L:
+	for h, elem := range ordered[0].anyMap {
+		for _, core := range ordered[1:] {
+			if !core.containsHash(h) {
+				continue L
+			}
+		}
+		intersection.addWithHash(elem, h)
+	}
+	return resultLike(sets[0], intersection)
+}
+
+// DifferenceAll provides a new set with the elements of the given base set that exist in none of the other sets.
+// The other sets are evaluated from the largest to the smallest one. All sets are locked once,
+// and the result is allocated a single time.
+// The result uses the same implementation and options as the base set.
+func DifferenceAll(base Set, others ...Set) Set {
+	cores, unlock := readLockAll(append([]Set{base}, others...))
+	defer unlock()
+
+	ordered := append([]*threadUnsafeSet(nil), cores[1:]...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Cardinality() > ordered[j].Cardinality()
+	})
+	difference := cores[0].derived(cores[0].Cardinality())
+L:
+	for h, elem := range cores[0].anyMap {
+		for _, core := range ordered {
+			if core.containsHash(h) {
+				continue L
+			}
+		}
+		difference.addWithHash(elem, h)
+	}
+	return resultLike(base, difference)
+}
+
+// derived provides an empty set with the options of this set and room for the given number of elements.
+// Derived sets are used after the lock of their source is released, while the hash cache and the hasher of a set
+// are only used under its lock. Therefore, a derived set has its own ones. It expects the set to be read-locked.
+func (set *threadUnsafeSet) derived(size int) *threadUnsafeSet {
+	derived := set.options.newThreadUnsafeSet()
+	derived.anyMap = make(map[uint64]interface{}, size)
+	return &derived
+}
+
+// derivedFrom provides a derived set of the given set, see derived. It read-locks the given set.
+func derivedFrom(source Set, size int) *threadUnsafeSet {
+	o := source.ThreadSafe()
+	o.RLock()
+	defer o.RUnlock()
+	return o.threadUnsafeSet.derived(size)
+}
+
+// resultLike provides the given result as thread-safe set unless the given template is not thread-safe.
+func resultLike(template Set, result *threadUnsafeSet) Set {
+	if _, ok := template.(*threadUnsafeSet); ok {
+		return result
+	}
+	return result.ThreadSafe()
+}
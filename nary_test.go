@@ -0,0 +1,86 @@
+package mapset
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_UnionAll(t *testing.T) {
+	a := NewSet(1, 2)
+	b := NewUnsafeSet(2, 3)
+	c := NewSet(4)
+
+	union := UnionAll(a, b, c)
+	assertEqual(union, NewSet(1, 2, 3, 4), t)
+	if _, ok := union.(*threadSafeSet); !ok {
+		t.Error("union should be thread-safe if the first set is thread-safe")
+	}
+	if _, ok := UnionAll(b, a).(*threadUnsafeSet); !ok {
+		t.Error("union should not be thread-safe if the first set is not thread-safe")
+	}
+	assertEqual(UnionAll(a, a), a, t)
+	assertEqual(UnionAll(), NewSet(), t)
+}
+
+func Test_IntersectAll(t *testing.T) {
+	a := NewSet(1, 2, 3, 4, 5)
+	b := NewUnsafeSet(2, 3, 4)
+	c := NewSet(3, 4, 6)
+
+	assertEqual(IntersectAll(a, b, c), NewSet(3, 4), t)
+	assertEqual(IntersectAll(a, b, NewSet()), NewSet(), t)
+	assertEqual(IntersectAll(a), a, t)
+	assertEqual(IntersectAll(), NewSet(), t)
+}
+
+func Test_DifferenceAll(t *testing.T) {
+	a := NewSet(1, 2, 3, 4, 5)
+	b := NewUnsafeSet(2, 3)
+	c := NewSet(5, 6)
+
+	assertEqual(DifferenceAll(a, b, c), NewSet(1, 4), t)
+	assertEqual(DifferenceAll(a), a, t)
+	assertEqual(DifferenceAll(a, a), NewSet(), t)
+}
+
+func Test_NaryConcurrent(t *testing.T) {
+	sets := make([]Set, 10)
+	for n := range sets {
+		sets[n] = makeSet([]int{n, n + 1, n + 2})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2 * len(sets))
+	for n := range sets {
+		go func(n int) {
+			UnionAll(sets[n], sets[len(sets)-1-n])
+			wg.Done()
+		}(n)
+		go func(n int) {
+			sets[n].Add(n + 3)
+			wg.Done()
+		}(n)
+	}
+	wg.Wait()
+
+	assertEqual(UnionAll(sets...), makeSet([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}), t)
+}
+
+func Test_derivedFrom(t *testing.T) {
+	options := SetOptions{Cache: true, Fingerprint: MultisetFingerprint}
+	source := options.New(1, 2)
+	core := source.CoreSet()
+	cached := len(core.hashCache)
+
+	derived := derivedFrom(source, 2)
+	derived.Add(3, NewSet(4))
+	if derived.options != options || derived.Cardinality() != 2 {
+		t.Errorf("derived set should have the options of its source, got %v", derived.options)
+	}
+	if len(core.hashCache) != cached || derived.hashOptions == core.hashOptions {
+		t.Error("derived set should not share the hash cache or the hasher of its source")
+	}
+	if !UnionAll(source, source).Equal(source) || len(core.hashCache) != cached {
+		t.Error("results of the n-ary operations should be derived from the first set")
+	}
+}
@@ -1,6 +1,10 @@
 package mapset
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
 
 // OrderedPair represents a 2-tuple of values.
 type OrderedPair struct {
@@ -9,15 +13,77 @@ type OrderedPair struct {
 }
 
 // Equal determines of this pair equals the given pair.
+// The values are compared deeply by their hashes, the same way a set determines its members.
 func (pair *OrderedPair) Equal(other OrderedPair) bool {
-	if pair.First == other.First &&
-		pair.Second == other.Second {
-		return true
-	}
-	return false
+	return pair.Hash() == other.Hash()
+}
+
+// Hash calculates the hash of the pair. It equals the hash of a Tuple with the same two values.
+// It always uses the default hasher, regardless of SetOptions.Hasher.
+func (pair OrderedPair) Hash() uint64 {
+	return hashSequence(pair.First, pair.Second)
+}
+
+// Swap provides the pair with the values in reverse order.
+func (pair OrderedPair) Swap() OrderedPair {
+	return OrderedPair{First: pair.Second, Second: pair.First}
+}
+
+// Tuple converts the pair to a Tuple with two values.
+func (pair OrderedPair) Tuple() Tuple {
+	return NewTuple(pair.First, pair.Second)
 }
 
 // String outputs a 2-tuple in the form "(A, B)".
 func (pair OrderedPair) String() string {
 	return fmt.Sprintf("(%v, %v)", pair.First, pair.Second)
 }
+
+// MarshalJSON creates a JSON array with the two values of the pair.
+func (pair OrderedPair) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{pair.First, pair.Second})
+}
+
+// UnmarshalJSON recreates a pair from a JSON array with two values.
+// Numbers are decoded as json.Number.
+func (pair *OrderedPair) UnmarshalJSON(b []byte) error {
+	values, err := decodeJSONArray(b)
+	if err != nil {
+		return err
+	}
+	if len(values) != 2 {
+		return fmt.Errorf("ordered pair requires two values, got %d", len(values))
+	}
+	pair.First, pair.Second = values[0], values[1]
+	return nil
+}
+
+// Project provides the set of the values at the given position of all pairs and tuples in the given set.
+// It panics if the set contains other elements or if a tuple is too short.
+func Project(set Set, i int) Set {
+	projection := derivedFrom(set, 0)
+	set.Each(func(elem interface{}) bool {
+		switch t := elem.(type) {
+		case OrderedPair:
+			projection.Add(t.Tuple().At(i))
+		case *OrderedPair:
+			projection.Add(t.Tuple().At(i))
+		case Tuple:
+			projection.Add(t.At(i))
+		default:
+			panic(fmt.Sprintf("cannot project %v of type %T", elem, elem))
+		}
+		return false
+	})
+	return resultLike(set, projection)
+}
+
+func decodeJSONArray(b []byte) ([]interface{}, error) {
+	var values []interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
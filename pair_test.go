@@ -0,0 +1,97 @@
+package mapset
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_OrderedPairEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    OrderedPair
+		b    OrderedPair
+		want bool
+	}{
+		{
+			name: "equal values",
+			a:    OrderedPair{First: 1, Second: "a"},
+			b:    OrderedPair{First: 1, Second: "a"},
+			want: true,
+		},
+		{
+			name: "swapped values",
+			a:    OrderedPair{First: 1, Second: "a"},
+			b:    OrderedPair{First: "a", Second: 1},
+			want: false,
+		},
+		{
+			name: "slices",
+			a:    OrderedPair{First: []int{1, 2}, Second: map[string]int{"a": 1}},
+			b:    OrderedPair{First: []int{1, 2}, Second: map[string]int{"a": 1}},
+			want: true,
+		},
+		{
+			name: "nested sets",
+			a:    OrderedPair{First: NewSet(1, 2), Second: nil},
+			b:    OrderedPair{First: NewSet(2, 1), Second: nil},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+			if got := NewSet(tt.a).Contains(tt.b); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_OrderedPairSwap(t *testing.T) {
+	pair := OrderedPair{First: 1, Second: "a"}
+	swapped := pair.Swap()
+	if !swapped.Equal(OrderedPair{First: "a", Second: 1}) {
+		t.Errorf("unexpected swapped pair %v", swapped)
+	}
+}
+
+func Test_OrderedPairJSON(t *testing.T) {
+	pair := OrderedPair{First: 1, Second: "a"}
+	b, err := json.Marshal(pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[1,"a"]` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+
+	var decoded OrderedPair
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.First != json.Number("1") || decoded.Second != "a" {
+		t.Errorf("unexpected decoded pair %v", decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`[1, 2, 3]`), &decoded); err == nil {
+		t.Error("a JSON array with three values should not be decoded as pair")
+	}
+}
+
+func Test_Project(t *testing.T) {
+	a := NewSet(1, 2)
+	b := NewSet("a", "b", "c")
+
+	assertEqual(Project(a.CartesianProduct(b), 0), a, t)
+	assertEqual(Project(a.CartesianProduct(b), 1), b, t)
+	assertEqual(Project(CartesianProductN(a, b, NewSet(true)), 2), NewSet(true), t)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("projecting a set of other elements should panic")
+		}
+	}()
+	Project(a, 0)
+}
@@ -0,0 +1,164 @@
+package mapset
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrNotEquivalence is returned if the equivalence classes of a relation are requested that is no equivalence.
+var ErrNotEquivalence = errors.New("relation is no equivalence")
+
+// Partition groups the elements of the given set by the given key function into a set of equivalence classes.
+// Elements are equivalent if their keys are equal. The classes are sets themselves, so that partitions can be compared
+// with Equal. The classes use the same implementation and options as the given set.
+// The keys must be comparable since they are used as map keys, Partition panics otherwise.
+func Partition(set Set, key func(interface{}) interface{}) Set {
+	groups := set.GroupBy(key)
+	partition := derivedFrom(set, len(groups))
+	for _, class := range groups {
+		partition.addWithHash(class, class.Hash())
+	}
+	return resultLike(set, partition)
+}
+
+// EquivalenceClasses provides the quotient set of the field of the relation, i.e., the set of its equivalence classes.
+// It returns ErrNotEquivalence if the relation is not reflexive, symmetric, and transitive.
+func (r *Relation) EquivalenceClasses() (Set, error) {
+	if !r.IsEquivalence() {
+		return nil, ErrNotEquivalence
+	}
+	classes := NewDisjointSets()
+	r.pairs.Each(func(elem interface{}) bool {
+		pair := elem.(OrderedPair)
+		classes.Union(pair.First, pair.Second)
+		return false
+	})
+	return classes.Partition(), nil
+}
+
+// IsPartition determines whether the given family of sets is a partition of the given universe,
+// i.e., whether its sets are non-empty, pairwise disjoint, and their union is the universe.
+// It returns false if the family contains elements that are no sets.
+func IsPartition(family, universe Set) bool {
+	covered := 0
+	valid := true
+	union := derivedFrom(universe, 0)
+	family.Each(func(elem interface{}) bool {
+		block, ok := elem.(Set)
+		if !ok || block.Empty() {
+			valid = false
+			return true
+		}
+		covered += block.Cardinality()
+		union.UnionWith(block)
+		return false
+	})
+	// the blocks are disjoint if none of their elements was counted twice
+	return valid && covered == union.Cardinality() && union.Equal(universe)
+}
+
+// Refine provides the coarsest common refinement of the given partitions, i.e., all non-empty intersections
+// of a block of the first partition with a block of the second one. It panics if a partition contains no sets.
+func Refine(a, b Set) Set {
+	refinement := derivedFrom(a, 0)
+	a.Each(func(x interface{}) bool {
+		b.Each(func(y interface{}) bool {
+			if block := x.(Set).Intersect(y.(Set)); !block.Empty() {
+				refinement.addWithHash(block, block.Hash())
+			}
+			return false
+		})
+		return false
+	})
+	return resultLike(a, refinement)
+}
+
+// PartitionsCardinality determines the number of partitions of the given set, i.e., the Bell number of its cardinality.
+func PartitionsCardinality(set Set) *big.Int {
+	return BellNumber(set.Cardinality())
+}
+
+// BellNumber determines the number of partitions of a set with n elements using the Bell triangle.
+func BellNumber(n int) *big.Int {
+	if n < 0 {
+		return new(big.Int)
+	}
+	row := []*big.Int{big.NewInt(1)}
+	for i := 0; i < n; i++ {
+		next := make([]*big.Int, len(row)+1)
+		next[0] = row[len(row)-1]
+		for j, v := range row {
+			next[j+1] = new(big.Int).Add(next[j], v)
+		}
+		row = next
+	}
+	return row[0]
+}
+
+// Partitions enumerates all partitions of the given set as sets of sets without materializing them in advance.
+// The number of partitions grows with the Bell number of the cardinality, see PartitionsCardinality.
+// Stop the returned iterator to end the enumeration early.
+// The partitions are thread-safe unless the given set was created with the Unsafe option.
+func Partitions(set Set) *Iterator {
+	c := newCombinator(set)
+	iterator, ch, stopCh := newIterator()
+
+	go func() {
+		defer close(ch)
+		n := len(c.hashes)
+		// restricted growth string: the block of every element is at most one more than the ones before it
+		blocks := make([]int, n)
+		for {
+			select {
+			case <-stopCh:
+				return
+			case ch <- c.partition(blocks):
+			}
+			i := n - 1
+			for ; i > 0; i-- {
+				highest := 0
+				for _, block := range blocks[:i] {
+					if block > highest {
+						highest = block
+					}
+				}
+				if blocks[i] <= highest {
+					break
+				}
+			}
+			if i <= 0 {
+				return
+			}
+			blocks[i]++
+			for j := i + 1; j < n; j++ {
+				blocks[j] = 0
+			}
+		}
+	}()
+
+	return iterator
+}
+
+// partition builds the partition that assigns every element to the given block.
+func (c *combinator) partition(blocks []int) Set {
+	safe := !c.template.options.Unsafe
+	classes := make([]*threadUnsafeSet, 0)
+	for i, block := range blocks {
+		if block == len(classes) {
+			classes = append(classes, c.template.derived(0))
+		}
+		classes[block].addWithHash(c.elements[i], c.hashes[i])
+	}
+	partition := c.template.derived(len(classes))
+	for _, class := range classes {
+		if safe {
+			partition.addWithHash(class.ThreadSafe(), class.Hash())
+		} else {
+			partition.addWithHash(class, class.Hash())
+		}
+	}
+	if safe {
+		return partition.ThreadSafe()
+	}
+	return partition
+}
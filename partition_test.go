@@ -0,0 +1,85 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_Partition(t *testing.T) {
+	set := NewSet(1, 2, 3, 4, 5)
+	byParity := Partition(set, func(elem interface{}) interface{} {
+		return elem.(int) % 2
+	})
+	want := NewSet(NewSet(1, 3, 5), NewSet(2, 4))
+	if !byParity.Equal(want) {
+		t.Errorf("expected %v, got %v", want, byParity)
+	}
+	if !IsPartition(byParity, set) {
+		t.Error("grouping should yield a partition")
+	}
+
+	tests := []struct {
+		name   string
+		family Set
+		want   bool
+	}{
+		{"overlapping", NewSet(NewSet(1, 2, 3), NewSet(3, 4, 5)), false},
+		{"incomplete", NewSet(NewSet(1, 2), NewSet(3, 4)), false},
+		{"foreign element", NewSet(NewSet(1, 2, 3, 4, 5, 6)), false},
+		{"empty block", NewSet(NewSet(1, 2, 3, 4, 5), NewSet()), false},
+		{"no sets", NewSet(1, 2, 3, 4, 5), false},
+		{"single block", NewSet(set), true},
+	}
+	for _, tt := range tests {
+		if got := IsPartition(tt.family, set); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func Test_Refine(t *testing.T) {
+	byParity := NewSet(NewSet(1, 3, 5), NewSet(2, 4, 6))
+	bySize := NewSet(NewSet(1, 2, 3), NewSet(4, 5, 6))
+	want := NewSet(NewSet(1, 3), NewSet(5), NewSet(2), NewSet(4, 6))
+	if refined := Refine(byParity, bySize); !refined.Equal(want) {
+		t.Errorf("expected %v, got %v", want, refined)
+	}
+}
+
+func Test_EquivalenceClasses(t *testing.T) {
+	r := NewRelation(OrderedPair{1, 2}, OrderedPair{3, 3}).SymmetricClosure().ReflexiveClosure()
+	classes, err := r.EquivalenceClasses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := NewSet(NewSet(1, 2), NewSet(3)); !classes.Equal(want) {
+		t.Errorf("expected %v, got %v", want, classes)
+	}
+
+	if _, err := NewRelation(OrderedPair{1, 2}).EquivalenceClasses(); err != ErrNotEquivalence {
+		t.Errorf("expected ErrNotEquivalence, got %v", err)
+	}
+}
+
+func Test_Partitions(t *testing.T) {
+	for n, want := range []int64{1, 1, 2, 5, 15, 52, 203} {
+		if got := BellNumber(n); got.Int64() != want {
+			t.Errorf("expected Bell number %d of %d, got %v", want, n, got)
+		}
+	}
+
+	set := NewSet(1, 2, 3, 4)
+	seen := NewSet()
+	for partition := range Partitions(set).C {
+		if !IsPartition(partition.(Set), set) {
+			t.Errorf("%v is no partition", partition)
+		}
+		seen.Add(partition)
+	}
+	if int64(seen.Cardinality()) != PartitionsCardinality(set).Int64() {
+		t.Errorf("expected %v distinct partitions, got %d", PartitionsCardinality(set), seen.Cardinality())
+	}
+
+	it := Partitions(NewSet(1, 2, 3, 4, 5, 6, 7, 8, 9, 10))
+	<-it.C
+	it.Stop()
+}
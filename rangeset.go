@@ -0,0 +1,439 @@
+package mapset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrRangeTooLarge is returned if a RangeSet has more points than can be converted to an explicit set.
+var ErrRangeTooLarge = errors.New("range set has too many points")
+
+// ErrRangeNotDiscrete is returned if a RangeSet with keys that are no DiscreteKey is converted to an explicit set.
+var ErrRangeNotDiscrete = errors.New("range set has no discrete keys")
+
+// Key is an ordered value that bounds the intervals of a RangeSet, e.g., an IntKey, a StringKey, or an IPKey.
+// Keys are only compared with keys of the same type, mixing key types in a RangeSet panics.
+type Key interface {
+	// Compare returns a negative number if the key is less than the given key, zero if both are equal,
+	// and a positive number otherwise.
+	Compare(other Key) int
+}
+
+// DiscreteKey is a Key that has a successor, so that the points of an interval can be counted and enumerated.
+type DiscreteKey interface {
+	Key
+
+	// Next provides the successor of the key. It returns false if the key is the greatest one.
+	Next() (DiscreteKey, bool)
+
+	// Distance provides the number of points from the key up to the given greater key.
+	Distance(other DiscreteKey) *big.Int
+}
+
+// IntKey is a discrete int64 key, e.g., a port or a Unix timestamp.
+type IntKey int64
+
+// Compare compares the key with the given IntKey.
+func (k IntKey) Compare(other Key) int {
+	o := other.(IntKey)
+	switch {
+	case k < o:
+		return -1
+	case k > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Next provides the successor of the key.
+func (k IntKey) Next() (DiscreteKey, bool) {
+	if k == math.MaxInt64 {
+		return k, false
+	}
+	return k + 1, true
+}
+
+// Distance provides the number of points from the key up to the given greater IntKey.
+func (k IntKey) Distance(other DiscreteKey) *big.Int {
+	return new(big.Int).SetUint64(uint64(other.(IntKey)) - uint64(k))
+}
+
+// FloatKey is a float64 key. Its intervals are continuous, so that their points can't be counted.
+type FloatKey float64
+
+// Compare compares the key with the given FloatKey.
+func (k FloatKey) Compare(other Key) int {
+	o := other.(FloatKey)
+	switch {
+	case k < o:
+		return -1
+	case k > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// StringKey is a string key that is ordered lexicographically by bytes, e.g., to partition a key space.
+type StringKey string
+
+// Compare compares the key with the given StringKey.
+func (k StringKey) Compare(other Key) int {
+	return strings.Compare(string(k), string(other.(StringKey)))
+}
+
+// IPKey is a discrete key of IPv4 and IPv6 addresses in their 16-byte form.
+// IPv4 addresses are ordered as their IPv4-mapped IPv6 addresses.
+type IPKey [net.IPv6len]byte
+
+// NewIPKey creates the key of the given IP address. It panics if the address is invalid.
+func NewIPKey(ip net.IP) (k IPKey) {
+	ip16 := ip.To16()
+	if ip16 == nil {
+		panic(fmt.Sprintf("invalid IP address %v", ip))
+	}
+	copy(k[:], ip16)
+	return
+}
+
+// IP provides the IP address of the key.
+func (k IPKey) IP() net.IP {
+	return append(net.IP(nil), k[:]...)
+}
+
+// Compare compares the key with the given IPKey.
+func (k IPKey) Compare(other Key) int {
+	o := other.(IPKey)
+	return bytes.Compare(k[:], o[:])
+}
+
+// Next provides the successor of the key.
+func (k IPKey) Next() (DiscreteKey, bool) {
+	for n := len(k) - 1; n >= 0; n-- {
+		k[n]++
+		if k[n] != 0 {
+			return k, true
+		}
+	}
+	return k, false
+}
+
+// Distance provides the number of points from the key up to the given greater IPKey.
+func (k IPKey) Distance(other DiscreteKey) *big.Int {
+	o := other.(IPKey)
+	return new(big.Int).Sub(new(big.Int).SetBytes(o[:]), new(big.Int).SetBytes(k[:]))
+}
+
+// String provides the IP address of the key.
+func (k IPKey) String() string {
+	return k.IP().String()
+}
+
+// Interval is the half-open range of keys [Start, End). It is empty if End is not greater than Start.
+type Interval struct {
+	Start Key
+	End   Key
+}
+
+// IntInterval provides the interval of the integers [start, end).
+func IntInterval(start, end int64) Interval {
+	return Interval{Start: IntKey(start), End: IntKey(end)}
+}
+
+// IPInterval provides the interval of the IP addresses [start, end).
+// It panics if an address is invalid.
+func IPInterval(start, end net.IP) Interval {
+	return Interval{Start: NewIPKey(start), End: NewIPKey(end)}
+}
+
+// Empty determines whether the interval contains no points.
+func (i Interval) Empty() bool {
+	return i.End.Compare(i.Start) <= 0
+}
+
+// Len provides the number of points in the interval. It panics if the keys are no DiscreteKey.
+func (i Interval) Len() *big.Int {
+	if i.Empty() {
+		return new(big.Int)
+	}
+	return i.Start.(DiscreteKey).Distance(i.End.(DiscreteKey))
+}
+
+// Contains determines whether the given point is in the interval.
+func (i Interval) Contains(v Key) bool {
+	return i.Start.Compare(v) <= 0 && v.Compare(i.End) < 0
+}
+
+// equal determines whether both intervals have equal bounds.
+func (i Interval) equal(other Interval) bool {
+	return i.Start.Compare(other.Start) == 0 && i.End.Compare(other.End) == 0
+}
+
+// String provides the interval in the notation [Start, End).
+func (i Interval) String() string {
+	return fmt.Sprintf("[%v, %v)", i.Start, i.End)
+}
+
+// RangeSet is a set of ordered keys that is stored as sorted list of coalesced intervals,
+// e.g., for port or IP ranges. Overlapping and adjacent intervals are merged.
+// All keys of a RangeSet must be of the same type.
+// Operations on a RangeSet are thread-safe.
+type RangeSet struct {
+	sync.RWMutex
+	intervals []Interval
+}
+
+// NewRangeSet creates a range set that contains the given intervals.
+func NewRangeSet(intervals ...Interval) *RangeSet {
+	set := &RangeSet{}
+	for _, i := range intervals {
+		set.AddRange(i.Start, i.End)
+	}
+	return set
+}
+
+// sweep combines two sorted lists of coalesced intervals.
+// The result contains the points for which keep accepts their membership in a and b.
+func sweep(a, b []Interval, keep func(inA, inB bool) bool) []Interval {
+	// the boundaries of a list are strictly ascending, even ones are starts and odd ones ends
+	boundary := func(intervals []Interval, n int) Key {
+		if n%2 == 0 {
+			return intervals[n/2].Start
+		}
+		return intervals[n/2].End
+	}
+	var result []Interval
+	var start Key
+	i, j := 0, 0
+	inA, inB, open := false, false, false
+	for i < 2*len(a) || j < 2*len(b) {
+		var x Key
+		if j == 2*len(b) || i < 2*len(a) && boundary(a, i).Compare(boundary(b, j)) <= 0 {
+			x = boundary(a, i)
+		} else {
+			x = boundary(b, j)
+		}
+		if i < 2*len(a) && boundary(a, i).Compare(x) == 0 {
+			inA = !inA
+			i++
+		}
+		if j < 2*len(b) && boundary(b, j).Compare(x) == 0 {
+			inB = !inB
+			j++
+		}
+		if k := keep(inA, inB); k && !open {
+			start, open = x, true
+		} else if !k && open {
+			result = append(result, Interval{Start: start, End: x})
+			open = false
+		}
+	}
+	return result
+}
+
+// snapshot provides a copy of the intervals.
+func (set *RangeSet) snapshot() []Interval {
+	set.RLock()
+	defer set.RUnlock()
+	return append([]Interval(nil), set.intervals...)
+}
+
+// combine replaces the intervals by combining them with the given ones.
+func (set *RangeSet) combine(other []Interval, keep func(inA, inB bool) bool) {
+	set.Lock()
+	defer set.Unlock()
+	set.intervals = sweep(set.intervals, other, keep)
+}
+
+// membership rules of the set operations for sweep
+func unionOf(inA, inB bool) bool               { return inA || inB }
+func intersectionOf(inA, inB bool) bool        { return inA && inB }
+func differenceOf(inA, inB bool) bool          { return inA && !inB }
+func symmetricDifferenceOf(inA, inB bool) bool { return inA != inB }
+
+// single provides the given range as list of intervals.
+func single(start, end Key) []Interval {
+	if i := (Interval{Start: start, End: end}); !i.Empty() {
+		return []Interval{i}
+	}
+	return nil
+}
+
+// AddRange adds all points of the half-open range [start, end).
+func (set *RangeSet) AddRange(start, end Key) {
+	set.combine(single(start, end), unionOf)
+}
+
+// RemoveRange removes all points of the half-open range [start, end).
+func (set *RangeSet) RemoveRange(start, end Key) {
+	set.combine(single(start, end), differenceOf)
+}
+
+// find provides the index of the first interval that ends after the given point.
+func (set *RangeSet) find(v Key) int {
+	return sort.Search(len(set.intervals), func(i int) bool { return set.intervals[i].End.Compare(v) > 0 })
+}
+
+// ContainsPoint determines whether the given point is in the set.
+func (set *RangeSet) ContainsPoint(v Key) bool {
+	set.RLock()
+	defer set.RUnlock()
+	i := set.find(v)
+	return i < len(set.intervals) && set.intervals[i].Contains(v)
+}
+
+// Covers determines whether all points of the half-open range [start, end) are in the set.
+// An empty range is always covered.
+func (set *RangeSet) Covers(start, end Key) bool {
+	if end.Compare(start) <= 0 {
+		return true
+	}
+	set.RLock()
+	defer set.RUnlock()
+	i := set.find(start)
+	return i < len(set.intervals) && set.intervals[i].Start.Compare(start) <= 0 && set.intervals[i].End.Compare(end) >= 0
+}
+
+// Gaps provides the intervals of the half-open range [start, end) that are not in the set.
+func (set *RangeSet) Gaps(start, end Key) []Interval {
+	set.RLock()
+	defer set.RUnlock()
+	return sweep(single(start, end), set.intervals, differenceOf)
+}
+
+// Intervals provides the coalesced intervals in ascending order.
+func (set *RangeSet) Intervals() []Interval {
+	return set.snapshot()
+}
+
+// Len provides the number of points in the set. It panics if the keys are no DiscreteKey.
+func (set *RangeSet) Len() *big.Int {
+	set.RLock()
+	defer set.RUnlock()
+	n := new(big.Int)
+	for _, i := range set.intervals {
+		n.Add(n, i.Len())
+	}
+	return n
+}
+
+// Empty determines whether the set contains no points.
+func (set *RangeSet) Empty() bool {
+	set.RLock()
+	defer set.RUnlock()
+	return len(set.intervals) == 0
+}
+
+// Clear removes all points.
+func (set *RangeSet) Clear() {
+	set.Lock()
+	defer set.Unlock()
+	set.intervals = nil
+}
+
+// Clone provides a copy of the set.
+func (set *RangeSet) Clone() *RangeSet {
+	return &RangeSet{intervals: set.snapshot()}
+}
+
+// Union provides the points that are in either set.
+func (set *RangeSet) Union(other *RangeSet) *RangeSet {
+	return &RangeSet{intervals: sweep(set.snapshot(), other.snapshot(), unionOf)}
+}
+
+// Intersect provides the points that are in both sets.
+func (set *RangeSet) Intersect(other *RangeSet) *RangeSet {
+	return &RangeSet{intervals: sweep(set.snapshot(), other.snapshot(), intersectionOf)}
+}
+
+// Difference provides the points that are in this set but not in the other one.
+func (set *RangeSet) Difference(other *RangeSet) *RangeSet {
+	return &RangeSet{intervals: sweep(set.snapshot(), other.snapshot(), differenceOf)}
+}
+
+// SymmetricDifference provides the points that are in either set but not in both.
+func (set *RangeSet) SymmetricDifference(other *RangeSet) *RangeSet {
+	return &RangeSet{intervals: sweep(set.snapshot(), other.snapshot(), symmetricDifferenceOf)}
+}
+
+// UnionWith adds all points of the other set.
+func (set *RangeSet) UnionWith(other *RangeSet) {
+	set.combine(other.snapshot(), unionOf)
+}
+
+// IntersectWith removes all points that are not in the other set.
+func (set *RangeSet) IntersectWith(other *RangeSet) {
+	set.combine(other.snapshot(), intersectionOf)
+}
+
+// DifferenceWith removes all points of the other set.
+func (set *RangeSet) DifferenceWith(other *RangeSet) {
+	set.combine(other.snapshot(), differenceOf)
+}
+
+// Equal determines whether both sets contain the same points.
+func (set *RangeSet) Equal(other *RangeSet) bool {
+	a, b := set.snapshot(), other.snapshot()
+	if len(a) != len(b) {
+		return false
+	}
+	for n := range a {
+		if !a[n].equal(b[n]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset determines whether all points of this set are in the other set.
+func (set *RangeSet) IsSubset(other *RangeSet) bool {
+	return len(sweep(set.snapshot(), other.snapshot(), differenceOf)) == 0
+}
+
+// IsSuperset determines whether all points of the other set are in this set.
+func (set *RangeSet) IsSuperset(other *RangeSet) bool {
+	return other.IsSubset(set)
+}
+
+// ToSet converts the range set to an explicit set of its keys.
+// It returns ErrRangeNotDiscrete if the keys are no DiscreteKey,
+// and ErrRangeTooLarge if the set contains more than the given number of points.
+func (set *RangeSet) ToSet(limit uint64) (Set, error) {
+	intervals := set.snapshot()
+	n := new(big.Int)
+	for _, i := range intervals {
+		if _, ok := i.Start.(DiscreteKey); !ok {
+			return nil, ErrRangeNotDiscrete
+		}
+		n.Add(n, i.Len())
+		if !n.IsUint64() || n.Uint64() > limit {
+			return nil, ErrRangeTooLarge
+		}
+	}
+	options := SetOptions{Cache: true}
+	result := options.newThreadSafeSet()
+	for _, i := range intervals {
+		for v, ok := i.Start.(DiscreteKey), true; ok && v.Compare(i.End) < 0; v, ok = v.Next() {
+			result.threadUnsafeSet.Add(v)
+		}
+	}
+	return &result, nil
+}
+
+// String provides the intervals of the set.
+func (set *RangeSet) String() string {
+	intervals := set.snapshot()
+	items := make([]string, 0, len(intervals))
+	for _, i := range intervals {
+		items = append(items, i.String())
+	}
+	return "RangeSet{" + strings.Join(items, ", ") + "}"
+}
@@ -0,0 +1,170 @@
+package mapset
+
+import (
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func Test_RangeSetCoalescing(t *testing.T) {
+	set := NewRangeSet(IntInterval(10, 20), IntInterval(30, 40))
+	set.AddRange(IntKey(20), IntKey(25))
+	set.AddRange(IntKey(5), IntKey(12))
+	set.AddRange(IntKey(50), IntKey(50))
+
+	want := []Interval{IntInterval(5, 25), IntInterval(30, 40)}
+	if got := set.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	set.AddRange(IntKey(24), IntKey(31))
+	if got := set.Intervals(); !reflect.DeepEqual(got, []Interval{IntInterval(5, 40)}) {
+		t.Errorf("overlapping ranges should be merged, got %v", got)
+	}
+
+	set.RemoveRange(IntKey(10), IntKey(15))
+	set.RemoveRange(IntKey(39), IntKey(100))
+	if got := set.Intervals(); !reflect.DeepEqual(got, []Interval{IntInterval(5, 10), IntInterval(15, 39)}) {
+		t.Errorf("removal should split ranges, got %v", got)
+	}
+	if set.Len().Int64() != 29 {
+		t.Errorf("expected 29 points, got %d", set.Len())
+	}
+	if s := set.String(); s != "RangeSet{[5, 10), [15, 39)}" {
+		t.Errorf("unexpected string %s", s)
+	}
+}
+
+func Test_RangeSetQueries(t *testing.T) {
+	ports := NewRangeSet(IntInterval(8000, 8100), IntInterval(9000, 9001))
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"start", ports.ContainsPoint(IntKey(8000)), true},
+		{"end is exclusive", ports.ContainsPoint(IntKey(8100)), false},
+		{"single port", ports.ContainsPoint(IntKey(9000)), true},
+		{"before", ports.ContainsPoint(IntKey(-1)), false},
+		{"covered", ports.Covers(IntKey(8010), IntKey(8100)), true},
+		{"gap", ports.Covers(IntKey(8050), IntKey(9001)), false},
+		{"empty range", ports.Covers(IntKey(5), IntKey(5)), true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+
+	want := []Interval{IntInterval(7990, 8000), IntInterval(8100, 9000), IntInterval(9001, 9010)}
+	if got := ports.Gaps(IntKey(7990), IntKey(9010)); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected gaps %v, got %v", want, got)
+	}
+}
+
+func Test_RangeSetAlgebra(t *testing.T) {
+	a := NewRangeSet(IntInterval(0, 10), IntInterval(20, 30))
+	b := NewRangeSet(IntInterval(5, 25))
+
+	tests := []struct {
+		name string
+		got  *RangeSet
+		want []Interval
+	}{
+		{"union", a.Union(b), []Interval{IntInterval(0, 30)}},
+		{"intersect", a.Intersect(b), []Interval{IntInterval(5, 10), IntInterval(20, 25)}},
+		{"difference", a.Difference(b), []Interval{IntInterval(0, 5), IntInterval(25, 30)}},
+		{
+			"symmetric difference",
+			a.SymmetricDifference(b),
+			[]Interval{IntInterval(0, 5), IntInterval(10, 20), IntInterval(25, 30)},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.got.Intervals(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	if !a.Intersect(b).IsSubset(a) || a.IsSubset(b) || !a.Union(b).IsSuperset(b) {
+		t.Error("subset relations should hold")
+	}
+	c := a.Clone()
+	c.IntersectWith(b)
+	c.UnionWith(NewRangeSet(IntInterval(10, 20)))
+	c.DifferenceWith(NewRangeSet(IntInterval(24, 25)))
+	if !c.Equal(NewRangeSet(IntInterval(5, 24))) {
+		t.Errorf("expected [5, 24), got %v", c)
+	}
+}
+
+func Test_RangeSetToSet(t *testing.T) {
+	set := NewRangeSet(IntInterval(-2, 1), IntInterval(5, 6))
+	explicit, err := set.ToSet(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explicit.Equal(NewSet(-2, -1, 0, 5)) {
+		t.Errorf("expected {-2, -1, 0, 5}, got %v", explicit)
+	}
+	if _, err := set.ToSet(3); err != ErrRangeTooLarge {
+		t.Errorf("expected ErrRangeTooLarge, got %v", err)
+	}
+}
+
+func Test_RangeSetIP(t *testing.T) {
+	ip := func(s string) IPKey {
+		return NewIPKey(net.ParseIP(s))
+	}
+	set := NewRangeSet(IPInterval(net.ParseIP("10.0.0.0"), net.ParseIP("10.0.1.0")))
+	set.AddRange(ip("10.0.1.0"), ip("10.0.2.0"))
+	set.AddRange(ip("2001:db8::"), ip("2001:db9::"))
+
+	if got := set.Intervals(); len(got) != 2 || got[0].String() != "[10.0.0.0, 10.0.2.0)" {
+		t.Errorf("adjacent IPv4 ranges should be merged, got %v", got)
+	}
+	if !set.ContainsPoint(ip("10.0.1.255")) || set.ContainsPoint(ip("10.0.2.0")) {
+		t.Error("IPv4 membership should respect the half-open bounds")
+	}
+	if !set.Covers(ip("2001:db8::1"), ip("2001:db8:ffff::")) || set.ContainsPoint(ip("2001:db9::")) {
+		t.Error("IPv6 membership should respect the half-open bounds")
+	}
+
+	want := new(big.Int).Lsh(big.NewInt(1), 96)
+	want.Add(want, big.NewInt(512))
+	if got := set.Len(); got.Cmp(want) != 0 {
+		t.Errorf("expected %v addresses, got %v", want, got)
+	}
+	if _, err := set.ToSet(1000); err != ErrRangeTooLarge {
+		t.Errorf("expected ErrRangeTooLarge, got %v", err)
+	}
+
+	small := NewRangeSet(IPInterval(net.ParseIP("192.168.0.254"), net.ParseIP("192.168.1.1")))
+	explicit, err := small.ToSet(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explicit.Equal(NewSet(ip("192.168.0.254"), ip("192.168.0.255"), ip("192.168.1.0"))) {
+		t.Errorf("unexpected addresses %v", explicit)
+	}
+}
+
+func Test_RangeSetContinuousKeys(t *testing.T) {
+	names := NewRangeSet(Interval{Start: StringKey("a"), End: StringKey("m")})
+	names.AddRange(StringKey("k"), StringKey("p"))
+	if !names.ContainsPoint(StringKey("mallory")) || names.ContainsPoint(StringKey("p")) {
+		t.Errorf("unexpected string membership in %v", names)
+	}
+
+	ratios := NewRangeSet(Interval{Start: FloatKey(0), End: FloatKey(0.5)})
+	ratios.RemoveRange(FloatKey(0.25), FloatKey(0.375))
+	want := []Interval{{Start: FloatKey(0), End: FloatKey(0.25)}, {Start: FloatKey(0.375), End: FloatKey(0.5)}}
+	if got := ratios.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if _, err := ratios.ToSet(10); err != ErrRangeNotDiscrete {
+		t.Errorf("expected ErrRangeNotDiscrete, got %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+package mapset
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCyclicRelation is returned if a relation that is sorted topologically contains a cycle.
+var ErrCyclicRelation = errors.New("relation contains a cycle")
+
+// Relation is a binary relation, i.e., a set of OrderedPair values such as the result of CartesianProduct.
+// Operations that derive a new relation leave the original one unchanged, the result uses the same
+// implementation and options as the underlying set.
+type Relation struct {
+	pairs Set
+}
+
+// NewRelation creates a relation that contains the given pairs.
+// Operations on the resulting relation are thread-safe.
+func NewRelation(pairs ...OrderedPair) *Relation {
+	set := NewSet()
+	for _, pair := range pairs {
+		set.Add(pair)
+	}
+	return &Relation{pairs: set}
+}
+
+// RelationOf provides the relation of the given set of pairs. The set is used directly, not copied.
+// It panics if the set contains other elements than OrderedPair values.
+func RelationOf(set Set) *Relation {
+	set.Each(func(elem interface{}) bool {
+		if _, ok := elem.(OrderedPair); !ok {
+			panic(fmt.Sprintf("relation requires OrderedPair elements, got %v of type %T", elem, elem))
+		}
+		return false
+	})
+	return &Relation{pairs: set}
+}
+
+// Pairs provides the underlying set of pairs.
+func (r *Relation) Pairs() Set {
+	return r.pairs
+}
+
+// Add adds the pair (a, b) to the relation.
+func (r *Relation) Add(a, b interface{}) {
+	r.pairs.Add(OrderedPair{First: a, Second: b})
+}
+
+// Contains determines whether the pair (a, b) is in the relation.
+func (r *Relation) Contains(a, b interface{}) bool {
+	return r.pairs.Contains(OrderedPair{First: a, Second: b})
+}
+
+// Cardinality provides the number of pairs.
+func (r *Relation) Cardinality() int {
+	return r.pairs.Cardinality()
+}
+
+// Equal determines whether both relations contain the same pairs.
+func (r *Relation) Equal(other *Relation) bool {
+	return r.pairs.Equal(other.pairs)
+}
+
+// String provides the pairs of the relation.
+func (r *Relation) String() string {
+	return r.pairs.String()
+}
+
+// Domain provides the set of the first values of all pairs.
+func (r *Relation) Domain() Set {
+	return Project(r.pairs, 0)
+}
+
+// Range provides the set of the second values of all pairs.
+func (r *Relation) Range() Set {
+	return Project(r.pairs, 1)
+}
+
+// Field provides the set of all values of all pairs, i.e., the union of domain and range.
+func (r *Relation) Field() Set {
+	g := r.graph()
+	field := g.hasher.derived(len(g.nodes))
+	for h, node := range g.nodes {
+		field.addWithHash(node, h)
+	}
+	return resultLike(r.pairs, field)
+}
+
+// Inverse provides the relation with all pairs swapped.
+func (r *Relation) Inverse() *Relation {
+	return r.derive(func(g *relationGraph, add func(a, b uint64)) {
+		for a, successors := range g.successors {
+			for b := range successors {
+				add(b, a)
+			}
+		}
+	})
+}
+
+// Compose provides the relation that contains (a, c) for all pairs (a, b) of this relation and (b, c) of the other.
+// In other words, it applies this relation first and the other one second.
+func (r *Relation) Compose(other *Relation) *Relation {
+	o := other.graph()
+	return r.derive(func(g *relationGraph, add func(a, b uint64)) {
+		for h, node := range o.nodes {
+			g.nodes[h] = node
+		}
+		for a, successors := range g.successors {
+			for b := range successors {
+				for c := range o.successors[b] {
+					add(a, c)
+				}
+			}
+		}
+	})
+}
+
+// TransitiveClosure provides the smallest transitive relation that contains this relation.
+func (r *Relation) TransitiveClosure() *Relation {
+	return r.derive(func(g *relationGraph, add func(a, b uint64)) {
+		for a := range g.successors {
+			for b := range g.reachable(a) {
+				add(a, b)
+			}
+		}
+	})
+}
+
+// ReflexiveClosure provides the smallest relation that contains this relation and (x, x) for all x of its field.
+func (r *Relation) ReflexiveClosure() *Relation {
+	return r.derive(func(g *relationGraph, add func(a, b uint64)) {
+		for a, successors := range g.successors {
+			for b := range successors {
+				add(a, b)
+			}
+		}
+		for h := range g.nodes {
+			add(h, h)
+		}
+	})
+}
+
+// SymmetricClosure provides the smallest symmetric relation that contains this relation.
+func (r *Relation) SymmetricClosure() *Relation {
+	return r.derive(func(g *relationGraph, add func(a, b uint64)) {
+		for a, successors := range g.successors {
+			for b := range successors {
+				add(a, b)
+				add(b, a)
+			}
+		}
+	})
+}
+
+// IsFunction determines whether every value of the domain is related to exactly one value.
+func (r *Relation) IsFunction() bool {
+	for _, successors := range r.graph().successors {
+		if len(successors) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsReflexive determines whether (x, x) is in the relation for all x of its field.
+func (r *Relation) IsReflexive() bool {
+	return r.graph().isReflexive()
+}
+
+// IsSymmetric determines whether (b, a) is in the relation for every pair (a, b).
+func (r *Relation) IsSymmetric() bool {
+	return r.graph().isSymmetric()
+}
+
+// IsAntisymmetric determines whether (a, b) and (b, a) are only both in the relation if a equals b.
+func (r *Relation) IsAntisymmetric() bool {
+	return r.graph().isAntisymmetric()
+}
+
+// IsTransitive determines whether (a, c) is in the relation for all pairs (a, b) and (b, c).
+func (r *Relation) IsTransitive() bool {
+	return r.graph().isTransitive()
+}
+
+// IsEquivalence determines whether the relation is reflexive, symmetric, and transitive.
+func (r *Relation) IsEquivalence() bool {
+	g := r.graph()
+	return g.isReflexive() && g.isSymmetric() && g.isTransitive()
+}
+
+// IsPartialOrder determines whether the relation is reflexive, antisymmetric, and transitive.
+func (r *Relation) IsPartialOrder() bool {
+	g := r.graph()
+	return g.isReflexive() && g.isAntisymmetric() && g.isTransitive()
+}
+
+// TopologicalSort orders the field of the relation so that a precedes b for every pair (a, b) with a other than b.
+// Pairs (x, x) are ignored, which allows to sort partial orders. The order of unrelated values is unspecified.
+// It returns ErrCyclicRelation if the relation contains a cycle of distinct values.
+func (r *Relation) TopologicalSort() ([]interface{}, error) {
+	g := r.graph()
+	indegree := make(map[uint64]int, len(g.nodes))
+	for a, successors := range g.successors {
+		for b := range successors {
+			if a != b {
+				indegree[b]++
+			}
+		}
+	}
+	queue := make([]uint64, 0, len(g.nodes))
+	for h := range g.nodes {
+		if indegree[h] == 0 {
+			queue = append(queue, h)
+		}
+	}
+	sorted := make([]interface{}, 0, len(g.nodes))
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, g.nodes[a])
+		for b := range g.successors[a] {
+			if a == b {
+				continue
+			}
+			indegree[b]--
+			if indegree[b] == 0 {
+				queue = append(queue, b)
+			}
+		}
+	}
+	if len(sorted) != len(g.nodes) {
+		return nil, ErrCyclicRelation
+	}
+	return sorted, nil
+}
+
+// relationGraph indexes the pairs of a relation by the hashes of their values.
+type relationGraph struct {
+	hasher     *threadUnsafeSet
+	nodes      map[uint64]interface{}
+	successors map[uint64]map[uint64]bool
+}
+
+func (r *Relation) graph() *relationGraph {
+	g := &relationGraph{
+		hasher:     derivedFrom(r.pairs, 0),
+		nodes:      make(map[uint64]interface{}),
+		successors: make(map[uint64]map[uint64]bool),
+	}
+	r.pairs.Each(func(elem interface{}) bool {
+		pair := elem.(OrderedPair)
+		a, b := g.hasher.hashFor(pair.First), g.hasher.hashFor(pair.Second)
+		g.nodes[a], g.nodes[b] = pair.First, pair.Second
+		if g.successors[a] == nil {
+			g.successors[a] = make(map[uint64]bool)
+		}
+		g.successors[a][b] = true
+		return false
+	})
+	return g
+}
+
+// derive builds a new relation from the pairs of value hashes that the given function adds.
+func (r *Relation) derive(build func(g *relationGraph, add func(a, b uint64))) *Relation {
+	g := r.graph()
+	result := g.hasher.derived(0)
+	build(g, func(a, b uint64) {
+		result.Add(OrderedPair{First: g.nodes[a], Second: g.nodes[b]})
+	})
+	return &Relation{pairs: resultLike(r.pairs, result)}
+}
+
+// reachable provides the hashes of all values that can be reached from the given one by one or more pairs.
+func (g *relationGraph) reachable(start uint64) map[uint64]bool {
+	visited := make(map[uint64]bool)
+	stack := []uint64{start}
+	for len(stack) > 0 {
+		a := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for b := range g.successors[a] {
+			if !visited[b] {
+				visited[b] = true
+				stack = append(stack, b)
+			}
+		}
+	}
+	return visited
+}
+
+func (g *relationGraph) isReflexive() bool {
+	for h := range g.nodes {
+		if !g.successors[h][h] {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *relationGraph) isSymmetric() bool {
+	for a, successors := range g.successors {
+		for b := range successors {
+			if !g.successors[b][a] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (g *relationGraph) isAntisymmetric() bool {
+	for a, successors := range g.successors {
+		for b := range successors {
+			if a != b && g.successors[b][a] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (g *relationGraph) isTransitive() bool {
+	for _, successors := range g.successors {
+		for b := range successors {
+			for c := range g.successors[b] {
+				if !successors[c] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_RelationBasics(t *testing.T) {
+	r := RelationOf(NewSet(1, 2).CartesianProduct(NewSet("a")))
+	r.Add(3, "b")
+
+	if !r.Domain().Equal(NewSet(1, 2, 3)) || !r.Range().Equal(NewSet("a", "b")) {
+		t.Errorf("unexpected domain %v or range %v", r.Domain(), r.Range())
+	}
+	if !r.Field().Equal(NewSet(1, 2, 3, "a", "b")) {
+		t.Errorf("unexpected field %v", r.Field())
+	}
+	if !r.IsFunction() {
+		t.Error("every value maps to one value")
+	}
+	if !r.Inverse().Equal(NewRelation(OrderedPair{"a", 1}, OrderedPair{"a", 2}, OrderedPair{"b", 3})) {
+		t.Errorf("unexpected inverse %v", r.Inverse())
+	}
+	if r.Inverse().IsFunction() {
+		t.Error("inverse maps a to two values")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("relation of non-pairs should panic")
+		}
+	}()
+	RelationOf(NewSet(1))
+}
+
+func Test_RelationCompose(t *testing.T) {
+	parent := NewRelation(OrderedPair{"ann", "bob"}, OrderedPair{"bob", "cid"}, OrderedPair{"bob", "dan"})
+	grandparent := parent.Compose(parent)
+	if !grandparent.Equal(NewRelation(OrderedPair{"ann", "cid"}, OrderedPair{"ann", "dan"})) {
+		t.Errorf("unexpected composition %v", grandparent)
+	}
+
+	ancestor := parent.TransitiveClosure()
+	if ancestor.Cardinality() != 5 || !ancestor.Contains("ann", "dan") || !ancestor.IsTransitive() {
+		t.Errorf("unexpected transitive closure %v", ancestor)
+	}
+	if parent.IsTransitive() {
+		t.Error("parent relation is not transitive")
+	}
+}
+
+func Test_RelationProperties(t *testing.T) {
+	sameParity := RelationOf(NewSet(1, 2, 3).CartesianProduct(NewSet(1, 2, 3)).Filter(func(elem interface{}) bool {
+		pair := elem.(OrderedPair)
+		return pair.First.(int)%2 == pair.Second.(int)%2
+	}))
+	if !sameParity.IsEquivalence() || sameParity.IsPartialOrder() {
+		t.Error("same parity is an equivalence but no partial order")
+	}
+
+	divides := NewRelation(OrderedPair{2, 4}, OrderedPair{4, 8}, OrderedPair{2, 6}).TransitiveClosure().ReflexiveClosure()
+	if !divides.IsPartialOrder() || divides.IsEquivalence() || divides.IsSymmetric() {
+		t.Error("divisibility is a partial order but no equivalence")
+	}
+	if !divides.SymmetricClosure().IsSymmetric() {
+		t.Error("symmetric closure should be symmetric")
+	}
+
+	sorted, err := divides.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	position := make(map[interface{}]int)
+	for n, elem := range sorted {
+		position[elem] = n
+	}
+	if len(sorted) != 4 || position[2] != 0 || position[4] > position[8] {
+		t.Errorf("unexpected order %v", sorted)
+	}
+
+	cyclic := NewRelation(OrderedPair{1, 2}, OrderedPair{2, 1})
+	if _, err := cyclic.TopologicalSort(); err != ErrCyclicRelation {
+		t.Errorf("expected ErrCyclicRelation, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+package mapset
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// chunkBits is the number of low bits of an integer that are stored in a container.
+	chunkBits = 16
+	// arrayMaxCardinality is the largest cardinality of an array container, beyond it bitmaps are smaller.
+	arrayMaxCardinality = 4096
+	// bitmapWords is the number of words of a bitmap container.
+	bitmapWords = 1 << chunkBits / 64
+)
+
+// container stores the low 16 bits of the integers of a 64K chunk.
+// Mutations return the container that replaces the receiver, which allows to switch its representation.
+type container interface {
+	cardinality() int
+	contains(low uint16) bool
+	add(low uint16) container
+	remove(low uint16) container
+	// each calls cb for all values in ascending order until it returns true and reports whether it was stopped.
+	each(cb func(low uint16) bool) bool
+	// bitmap provides the container as bitmap, which may be the receiver itself.
+	bitmap() *bitmapContainer
+	clone() container
+	// size estimates the memory footprint of the container in bytes.
+	size() int
+}
+
+// arrayContainer stores a sorted list of values for sparse chunks.
+type arrayContainer []uint16
+
+func (c arrayContainer) cardinality() int {
+	return len(c)
+}
+
+func (c arrayContainer) search(low uint16) int {
+	return sort.Search(len(c), func(i int) bool { return c[i] >= low })
+}
+
+func (c arrayContainer) contains(low uint16) bool {
+	i := c.search(low)
+	return i < len(c) && c[i] == low
+}
+
+func (c arrayContainer) add(low uint16) container {
+	i := c.search(low)
+	if i < len(c) && c[i] == low {
+		return c
+	}
+	if len(c) == arrayMaxCardinality {
+		b := c.bitmap()
+		return b.add(low)
+	}
+	c = append(c, 0)
+	copy(c[i+1:], c[i:])
+	c[i] = low
+	return c
+}
+
+func (c arrayContainer) remove(low uint16) container {
+	i := c.search(low)
+	if i == len(c) || c[i] != low {
+		return c
+	}
+	return append(c[:i], c[i+1:]...)
+}
+
+func (c arrayContainer) each(cb func(low uint16) bool) bool {
+	for _, low := range c {
+		if cb(low) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c arrayContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, low := range c {
+		b.words[low/64] |= 1 << (low % 64)
+	}
+	b.card = len(c)
+	return b
+}
+
+func (c arrayContainer) clone() container {
+	return append(arrayContainer(nil), c...)
+}
+
+func (c arrayContainer) size() int {
+	return 2 * len(c)
+}
+
+// bitmapContainer stores one bit per value for dense chunks.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.card
+}
+
+func (c *bitmapContainer) contains(low uint16) bool {
+	return c.words[low/64]&(1<<(low%64)) != 0
+}
+
+func (c *bitmapContainer) add(low uint16) container {
+	if !c.contains(low) {
+		c.words[low/64] |= 1 << (low % 64)
+		c.card++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(low uint16) container {
+	if c.contains(low) {
+		c.words[low/64] &^= 1 << (low % 64)
+		c.card--
+	}
+	return normalize(c)
+}
+
+func (c *bitmapContainer) each(cb func(low uint16) bool) bool {
+	for n, word := range c.words {
+		for word != 0 {
+			low := uint16(n*64 + bits.TrailingZeros64(word))
+			if cb(low) {
+				return true
+			}
+			word &= word - 1
+		}
+	}
+	return false
+}
+
+func (c *bitmapContainer) bitmap() *bitmapContainer {
+	return c
+}
+
+func (c *bitmapContainer) clone() container {
+	copied := *c
+	return &copied
+}
+
+func (c *bitmapContainer) size() int {
+	return 8 * bitmapWords
+}
+
+func (c *bitmapContainer) count() {
+	c.card = 0
+	for _, word := range c.words {
+		c.card += bits.OnesCount64(word)
+	}
+}
+
+// interval16 is an inclusive range of values.
+type interval16 struct {
+	start uint16
+	last  uint16
+}
+
+// runContainer stores sorted, non-adjacent ranges of values for chunks with long runs.
+// Mutations convert it to an array or bitmap container.
+type runContainer []interval16
+
+func (c runContainer) cardinality() (card int) {
+	for _, run := range c {
+		card += int(run.last-run.start) + 1
+	}
+	return
+}
+
+func (c runContainer) contains(low uint16) bool {
+	i := sort.Search(len(c), func(i int) bool { return c[i].last >= low })
+	return i < len(c) && c[i].start <= low
+}
+
+func (c runContainer) add(low uint16) container {
+	if c.contains(low) {
+		return c
+	}
+	return normalize(c.bitmap()).add(low)
+}
+
+func (c runContainer) remove(low uint16) container {
+	if !c.contains(low) {
+		return c
+	}
+	return c.bitmap().remove(low)
+}
+
+func (c runContainer) each(cb func(low uint16) bool) bool {
+	for _, run := range c {
+		for low := int(run.start); low <= int(run.last); low++ {
+			if cb(uint16(low)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c runContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, run := range c {
+		for low := int(run.start); low <= int(run.last); low++ {
+			b.words[low/64] |= 1 << (uint(low) % 64)
+		}
+	}
+	b.count()
+	return b
+}
+
+func (c runContainer) clone() container {
+	return append(runContainer(nil), c...)
+}
+
+func (c runContainer) size() int {
+	return 4 * len(c)
+}
+
+// normalize converts a bitmap to an array container if that is smaller.
+func normalize(b *bitmapContainer) container {
+	if b.card > arrayMaxCardinality {
+		return b
+	}
+	array := make(arrayContainer, 0, b.card)
+	b.each(func(low uint16) bool {
+		array = append(array, low)
+		return false
+	})
+	return array
+}
+
+// optimize converts the given container to a run container if that is smaller.
+func optimize(c container) container {
+	var runs runContainer
+	c.each(func(low uint16) bool {
+		if n := len(runs); n > 0 && runs[n-1].last+1 == low {
+			runs[n-1].last = low
+		} else {
+			runs = append(runs, interval16{start: low, last: low})
+		}
+		return false
+	})
+	if runs.size() < c.size() {
+		return runs
+	}
+	if _, ok := c.(runContainer); ok {
+		// the runs aren't worth it anymore
+		return normalize(c.bitmap())
+	}
+	return c
+}
+
+// containerOp is a binary set operation on containers.
+type containerOp struct {
+	// onlyA, onlyB, and both determine which values are kept.
+	onlyA bool
+	onlyB bool
+	both  bool
+	// word combines two words of bitmaps.
+	word func(a, b uint64) uint64
+}
+
+var (
+	opOr     = containerOp{onlyA: true, onlyB: true, both: true, word: func(a, b uint64) uint64 { return a | b }}
+	opAnd    = containerOp{both: true, word: func(a, b uint64) uint64 { return a & b }}
+	opAndNot = containerOp{onlyA: true, word: func(a, b uint64) uint64 { return a &^ b }}
+	opXor    = containerOp{onlyA: true, onlyB: true, word: func(a, b uint64) uint64 { return a ^ b }}
+)
+
+// apply combines two containers into a new one. The result may be empty.
+func (op containerOp) apply(a, b container) container {
+	x, aArray := a.(arrayContainer)
+	y, bArray := b.(arrayContainer)
+	switch {
+	case aArray && bArray:
+		return op.merge(x, y)
+	case aArray && !op.onlyB:
+		// intersections and differences of a sparse container only need lookups
+		return op.filter(x, b)
+	case bArray && !op.onlyA && !op.onlyB:
+		return op.filter(y, a)
+	}
+	ab, bb := a.bitmap(), b.bitmap()
+	result := &bitmapContainer{}
+	for n := range result.words {
+		result.words[n] = op.word(ab.words[n], bb.words[n])
+	}
+	result.count()
+	return normalize(result)
+}
+
+func (op containerOp) merge(a, b arrayContainer) container {
+	result := make(arrayContainer, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || i < len(a) && a[i] < b[j]:
+			if op.onlyA {
+				result = append(result, a[i])
+			}
+			i++
+		case i == len(a) || b[j] < a[i]:
+			if op.onlyB {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if op.both {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if len(result) > arrayMaxCardinality {
+		return normalize(result.bitmap())
+	}
+	return result
+}
+
+func (op containerOp) filter(a arrayContainer, b container) container {
+	result := make(arrayContainer, 0, len(a))
+	for _, low := range a {
+		if b.contains(low) == op.both {
+			result = append(result, low)
+		}
+	}
+	return result
+}
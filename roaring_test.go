@@ -0,0 +1,66 @@
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_ContainerOps(t *testing.T) {
+	arrays := func(values ...uint16) container {
+		var c container = arrayContainer(nil)
+		for _, v := range values {
+			c = c.add(v)
+		}
+		return c
+	}
+	dense := func(start, end int) container {
+		var c container = arrayContainer(nil)
+		for v := start; v < end; v++ {
+			c = c.add(uint16(v))
+		}
+		return c
+	}
+
+	tests := []struct {
+		name string
+		op   containerOp
+		a    container
+		b    container
+		want int
+	}{
+		{"array or array", opOr, arrays(1, 2, 3), arrays(3, 4), 4},
+		{"array and bitmap", opAnd, arrays(1, 5000, 7000), dense(0, 6000), 2},
+		{"bitmap and array", opAnd, dense(0, 6000), arrays(1, 7000), 1},
+		{"array andnot bitmap", opAndNot, arrays(1, 7000), dense(0, 6000), 1},
+		{"bitmap xor bitmap", opXor, dense(0, 6000), dense(3000, 9000), 6000},
+		{"run or array", opOr, optimize(dense(0, 100)), arrays(200), 101},
+		{"bitmap andnot run", opAndNot, dense(0, 6000), optimize(dense(0, 5000)), 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.op.apply(tt.a, tt.b)
+			if got.cardinality() != tt.want {
+				t.Errorf("expected %d values, got %d", tt.want, got.cardinality())
+			}
+			if _, ok := got.(*bitmapContainer); ok != (tt.want > arrayMaxCardinality) {
+				t.Errorf("result has the wrong representation %T", got)
+			}
+		})
+	}
+}
+
+func Test_ContainerConversion(t *testing.T) {
+	var c container = arrayContainer(nil)
+	for v := 0; v <= arrayMaxCardinality; v++ {
+		c = c.add(uint16(v * 2))
+	}
+	if _, ok := c.(*bitmapContainer); !ok {
+		t.Fatalf("array should become a bitmap beyond %d values, got %T", arrayMaxCardinality, c)
+	}
+	c = c.remove(0)
+	if _, ok := c.(arrayContainer); !ok {
+		t.Fatalf("bitmap should become an array again, got %T", c)
+	}
+	if _, ok := optimize(c).(arrayContainer); !ok {
+		t.Error("values without runs should stay an array")
+	}
+}
@@ -16,6 +16,10 @@ import (
 type Set interface {
 	hashstructure.Hashable
 
+	// Fingerprint provides the 128-bit fingerprint of the set as selected by the FingerprintScheme of its options.
+	// Hash doesn't depend on the scheme, so that equal sets of different schemes have equal hashes.
+	Fingerprint() Fingerprint
+
 	// UpdateHash updates the currently calculated hash of the set.
 	// Use it if underlying elements are mutable and once they may have been changed.
 	// It's not necessary to update the hashes but comparators will treat the element as if was not changed.
@@ -31,6 +35,34 @@ type Set interface {
 	// Empty determines if the set is empty.
 	Empty() bool
 
+	// UnionWith adds all elements of the given set to this set, like the |= operator in Python.
+	UnionWith(other Set)
+
+	// IntersectWith removes all elements from this set that are not in the given set, like the &= operator in Python.
+	IntersectWith(other Set)
+
+	// DifferenceWith removes all elements of the given set from this set, like the -= operator in Python.
+	DifferenceWith(other Set)
+
+	// SymmetricDifferenceWith removes all elements of the given set that are in this set and adds all others,
+	// like the ^= operator in Python.
+	SymmetricDifferenceWith(other Set)
+
+	// Jaccard determines the Jaccard similarity of this set and the given set,
+	// i.e., the cardinality of their intersection divided by the cardinality of their union.
+	// Two empty sets have a similarity of 1.
+	Jaccard(other Set) float64
+
+	// Overlap determines the overlap coefficient of this set and the given set,
+	// i.e., the cardinality of their intersection divided by the cardinality of the smaller set.
+	// Two empty sets have a coefficient of 1, an empty and a non-empty set have a coefficient of 0.
+	Overlap(other Set) float64
+
+	// Dice determines the Sørensen–Dice coefficient of this set and the given set,
+	// i.e., twice the cardinality of their intersection divided by the sum of their cardinalities.
+	// Two empty sets have a coefficient of 1.
+	Dice(other Set) float64
+
 	// Clear removes all elements from the set, leaving the empty set.
 	Clear()
 
@@ -88,6 +120,35 @@ type Set interface {
 	// If passed func returns true, stop iteration eagerly.
 	Each(func(interface{}) bool)
 
+	// Filter provides a new set with all elements that satisfy the given predicate.
+	Filter(predicate func(interface{}) bool) Set
+
+	// Map provides a new set with the results of the given mapper for all elements.
+	// The results are hashed again, so that equal results are only contained once.
+	Map(mapper func(interface{}) interface{}) Set
+
+	// Partition splits the set into a new set with all elements that satisfy the given predicate
+	// and a new set with the rest of the elements.
+	Partition(predicate func(interface{}) bool) (matching, rest Set)
+
+	// GroupBy splits the set into new sets of elements with equal keys.
+	// The keys must be comparable since they are used as map keys.
+	GroupBy(key func(interface{}) interface{}) map[interface{}]Set
+
+	// Reduce combines all elements to a single value by passing the given reducer the result of its previous call,
+	// starting with the given initial value, and the next element.
+	// Since sets are unordered, the reducer should be commutative.
+	Reduce(reducer func(accumulator, elem interface{}) interface{}, initial interface{}) interface{}
+
+	// Any determines if at least one element satisfies the given predicate.
+	Any(predicate func(interface{}) bool) bool
+
+	// All determines if all elements satisfy the given predicate.
+	All(predicate func(interface{}) bool) bool
+
+	// Count determines the number of elements that satisfy the given predicate.
+	Count(predicate func(interface{}) bool) int
+
 	// Iter returns a channel of elements that you can range over.
 	Iter() <-chan interface{}
 
@@ -122,6 +183,10 @@ type Set interface {
 	// CartesianProduct builds the Cartesian Product of this set and the given set.
 	CartesianProduct(other Set) Set
 
+	// Sketch builds a HyperLogLog sketch of the given precision from the element hashes of the set.
+	// Sketches of different sets can be merged to estimate the cardinality of their union.
+	Sketch(precision uint8) *Sketch
+
 	// ToSlice converts the members of the set as to a slice.
 	ToSlice() []interface{}
 
@@ -150,6 +215,13 @@ type SetOptions struct {
 	Unsafe bool
 	// Hasher overrides the default hash function.
 	Hasher hash.Hash64
+	// Fingerprint selects how the element hashes are combined to the fingerprint of the set.
+	// The default XORFingerprint is the fastest but also the least collision-resistant scheme.
+	Fingerprint FingerprintScheme
+	// StrictEqual makes Equal compare the sets element-wise if their fingerprints match.
+	// It also makes Contains check every given element individually.
+	// Use it if the certainty of the comparison is more important than its performance.
+	StrictEqual bool
 }
 
 // NewSet creates a set that contains the given elements.
@@ -1337,3 +1337,144 @@ func Test_ToSliceUnthreadsafe(t *testing.T) {
 		}
 	}
 }
+
+func Test_SimilarityCoefficients(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       []int
+		b       []int
+		jaccard float64
+		overlap float64
+		dice    float64
+	}{
+		{name: "empty sets", a: []int{}, b: []int{}, jaccard: 1, overlap: 1, dice: 1},
+		{name: "one empty set", a: []int{1}, b: []int{}, jaccard: 0, overlap: 0, dice: 0},
+		{name: "disjoint sets", a: []int{1, 2}, b: []int{3, 4}, jaccard: 0, overlap: 0, dice: 0},
+		{name: "equal sets", a: []int{1, 2}, b: []int{2, 1}, jaccard: 1, overlap: 1, dice: 1},
+		{name: "subset", a: []int{1, 2}, b: []int{1, 2, 3, 4}, jaccard: 0.5, overlap: 1, dice: 4.0 / 6},
+		{name: "overlapping sets", a: []int{1, 2, 3}, b: []int{2, 3, 4}, jaccard: 0.5, overlap: 2.0 / 3, dice: 2.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sets := range [][2]Set{
+				{makeSet(tt.a), makeSet(tt.b)},
+				{makeUnsafeSet(tt.b), makeUnsafeSet(tt.a)},
+			} {
+				a, b := sets[0], sets[1]
+				if got := a.Jaccard(b); got != tt.jaccard {
+					t.Errorf("Jaccard() = %v, want %v", got, tt.jaccard)
+				}
+				if got := a.Overlap(b); got != tt.overlap {
+					t.Errorf("Overlap() = %v, want %v", got, tt.overlap)
+				}
+				if got := a.Dice(b); got != tt.dice {
+					t.Errorf("Dice() = %v, want %v", got, tt.dice)
+				}
+			}
+		})
+	}
+}
+
+func Test_InPlaceOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(a, b Set)
+		want []int
+	}{
+		{name: "UnionWith", op: Set.UnionWith, want: []int{1, 2, 3, 4, 5}},
+		{name: "IntersectWith", op: Set.IntersectWith, want: []int{3}},
+		{name: "DifferenceWith", op: Set.DifferenceWith, want: []int{1, 2}},
+		{name: "SymmetricDifferenceWith", op: Set.SymmetricDifferenceWith, want: []int{1, 2, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sets := range [][2]Set{
+				{makeSet([]int{1, 2, 3}), makeSet([]int{3, 4, 5})},
+				{makeUnsafeSet([]int{1, 2, 3}), makeUnsafeSet([]int{3, 4, 5})},
+				{makeSet([]int{1, 2, 3}), makeUnsafeSet([]int{3, 4, 5})},
+			} {
+				a, b := sets[0], sets[1]
+				tt.op(a, b)
+				want := makeSet(tt.want)
+				assertEqual(a, want, t)
+				if a.Hash() != want.Hash() {
+					t.Errorf("hash of %v was not maintained", a)
+				}
+				assertEqual(b, makeSet([]int{3, 4, 5}), t)
+			}
+		})
+	}
+}
+
+func Test_InPlaceOperationsWithItself(t *testing.T) {
+	a := makeSet([]int{1, 2, 3})
+
+	a.UnionWith(a)
+	assertEqual(a, makeSet([]int{1, 2, 3}), t)
+	a.IntersectWith(a)
+	assertEqual(a, makeSet([]int{1, 2, 3}), t)
+	a.SymmetricDifferenceWith(a)
+	assertEqual(a, NewSet(), t)
+
+	a.Add(1, 2)
+	a.DifferenceWith(a)
+	assertEqual(a, NewSet(), t)
+	if a.Hash() != 0 {
+		t.Error("hash of the empty set should be zero")
+	}
+}
+
+func Test_FunctionalOperations(t *testing.T) {
+	isEven := func(i interface{}) bool {
+		return i.(int)%2 == 0
+	}
+	for _, a := range []Set{makeSet([]int{1, 2, 3, 4, 5}), makeUnsafeSet([]int{1, 2, 3, 4, 5})} {
+		_, safe := a.(*threadSafeSet)
+
+		filtered := a.Filter(isEven)
+		assertEqual(filtered, makeSet([]int{2, 4}), t)
+		if _, ok := filtered.(*threadSafeSet); ok != safe {
+			t.Error("filtered set should have the same thread safety as the receiver")
+		}
+
+		assertEqual(a.Map(func(i interface{}) interface{} { return i.(int) / 2 }), makeSet([]int{0, 1, 2}), t)
+
+		even, odd := a.Partition(isEven)
+		assertEqual(even, makeSet([]int{2, 4}), t)
+		assertEqual(odd, makeSet([]int{1, 3, 5}), t)
+
+		groups := a.GroupBy(func(i interface{}) interface{} { return i.(int) % 3 })
+		if len(groups) != 3 {
+			t.Errorf("expected 3 groups, got %d", len(groups))
+		}
+		assertEqual(groups[0], makeSet([]int{3}), t)
+		assertEqual(groups[1], makeSet([]int{1, 4}), t)
+		assertEqual(groups[2], makeSet([]int{2, 5}), t)
+
+		sum := a.Reduce(func(accumulator, elem interface{}) interface{} {
+			return accumulator.(int) + elem.(int)
+		}, 0)
+		if sum != 15 {
+			t.Errorf("expected sum 15, got %v", sum)
+		}
+
+		if !a.Any(isEven) || a.All(isEven) || a.Count(isEven) != 2 {
+			t.Error("unexpected results of Any, All or Count")
+		}
+	}
+}
+
+func Test_FunctionalOperationsOptions(t *testing.T) {
+	options := SetOptions{Fingerprint: MultisetFingerprint}
+	a := options.New(1, 2, 3)
+
+	filtered := a.Filter(func(interface{}) bool { return true })
+	if filtered.Fingerprint() != a.Fingerprint() {
+		t.Error("filtered set should use the fingerprint scheme of the receiver")
+	}
+
+	empty := NewSet()
+	if empty.Any(func(interface{}) bool { return true }) || !empty.All(func(interface{}) bool { return false }) {
+		t.Error("Any should be false and All should be true for the empty set")
+	}
+}
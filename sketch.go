@@ -0,0 +1,159 @@
+package mapset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	// MinSketchPrecision is the smallest supported precision of a Sketch.
+	MinSketchPrecision = 4
+	// MaxSketchPrecision is the largest supported precision of a Sketch.
+	MaxSketchPrecision = 18
+	// sketchVersion is the version of the binary encoding of a Sketch.
+	sketchVersion = 1
+)
+
+// ErrSketchPrecision is returned if two sketches with different precisions are merged.
+var ErrSketchPrecision = errors.New("sketches have different precisions")
+
+// Sketch is a HyperLogLog cardinality sketch that estimates the number of distinct elements it was fed with.
+// It uses 2^precision registers of one byte. Its standard error is about 1.04/sqrt(2^precision).
+// Operations on a Sketch are not thread-safe.
+type Sketch struct {
+	precision uint8
+	registers []uint8
+	hasher    *threadUnsafeSet
+}
+
+// NewSketch creates an empty sketch with the given precision that hashes elements like a set with default options.
+// It panics if the precision is not in the range of MinSketchPrecision to MaxSketchPrecision.
+func NewSketch(precision uint8) *Sketch {
+	options := SetOptions{}
+	hasher := options.newThreadUnsafeSet()
+	return newSketch(precision, &hasher)
+}
+
+func newSketch(precision uint8, hasher *threadUnsafeSet) *Sketch {
+	if precision < MinSketchPrecision || precision > MaxSketchPrecision {
+		panic(fmt.Sprintf("sketch precision %d is out of range [%d, %d]",
+			precision, MinSketchPrecision, MaxSketchPrecision))
+	}
+	return &Sketch{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+		hasher:    hasher,
+	}
+}
+
+// EstimateUnionCardinality estimates the cardinality of the union of the given sets without building the union.
+func EstimateUnionCardinality(precision uint8, sets ...Set) uint64 {
+	sketch := NewSketch(precision)
+	for _, set := range sets {
+		_ = sketch.Merge(set.Sketch(precision))
+	}
+	return sketch.Estimate()
+}
+
+// Precision provides the precision of the sketch.
+func (s *Sketch) Precision() uint8 {
+	return s.precision
+}
+
+// Add the given elements to this sketch.
+func (s *Sketch) Add(i ...interface{}) {
+	for _, val := range i {
+		s.AddHash(s.hasher.hashFor(val))
+	}
+}
+
+// AddHash adds an element to this sketch by its element hash.
+func (s *Sketch) AddHash(h uint64) {
+	x := mix64(h)
+	register := x >> (64 - s.precision)
+	rank := uint8(bits.LeadingZeros64(x<<s.precision|1<<(s.precision-1))) + 1
+	if rank > s.registers[register] {
+		s.registers[register] = rank
+	}
+}
+
+// Merge adds all elements that the given sketch was fed with to this sketch.
+// Both sketches must have the same precision, otherwise ErrSketchPrecision is returned.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.precision != other.precision {
+		return ErrSketchPrecision
+	}
+	for n, rank := range other.registers {
+		if rank > s.registers[n] {
+			s.registers[n] = rank
+		}
+	}
+	return nil
+}
+
+// Estimate approximates the number of distinct elements the sketch was fed with.
+func (s *Sketch) Estimate() uint64 {
+	m := float64(len(s.registers))
+	var sum float64
+	var zeros int
+	for _, rank := range s.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	estimate := sketchAlpha(len(s.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Use linear counting for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// Clone produces a copy of the sketch.
+func (s *Sketch) Clone() *Sketch {
+	return &Sketch{
+		precision: s.precision,
+		registers: append([]uint8(nil), s.registers...),
+		hasher:    s.hasher,
+	}
+}
+
+// MarshalBinary encodes the registers of the sketch.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 2, 2+len(s.registers))
+	data[0] = sketchVersion
+	data[1] = s.precision
+	return append(data, s.registers...), nil
+}
+
+// UnmarshalBinary decodes a sketch that was encoded by MarshalBinary.
+// A decoded zero sketch hashes elements like a set with default options.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != sketchVersion ||
+		data[1] < MinSketchPrecision || data[1] > MaxSketchPrecision ||
+		len(data)-2 != 1<<data[1] {
+		return errors.New("invalid sketch encoding")
+	}
+	s.precision = data[1]
+	s.registers = append([]uint8(nil), data[2:]...)
+	if s.hasher == nil {
+		s.hasher = NewSketch(MinSketchPrecision).hasher
+	}
+	return nil
+}
+
+func sketchAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
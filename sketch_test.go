@@ -0,0 +1,94 @@
+package mapset
+
+import (
+	"math"
+	"testing"
+)
+
+func assertEstimate(t *testing.T, got uint64, want int, tolerance float64) {
+	t.Helper()
+	if math.Abs(float64(got)-float64(want)) > tolerance*float64(want) {
+		t.Errorf("estimate %d is too far from %d", got, want)
+	}
+}
+
+func Test_SketchEstimate(t *testing.T) {
+	sketch := NewSketch(14)
+	for i := 0; i < 100000; i++ {
+		sketch.Add(i)
+		sketch.Add(i)
+	}
+	assertEstimate(t, sketch.Estimate(), 100000, 0.03)
+}
+
+func Test_SketchSmallCardinality(t *testing.T) {
+	sketch := NewSketch(12)
+	if sketch.Estimate() != 0 {
+		t.Errorf("empty sketch should estimate zero, got %d", sketch.Estimate())
+	}
+	sketch.Add("one", "two", "three")
+	if sketch.Estimate() != 3 {
+		t.Errorf("small sketch should estimate exactly, got %d", sketch.Estimate())
+	}
+}
+
+func Test_SetSketch(t *testing.T) {
+	a := NewSet()
+	b := NewUnsafeSet()
+	for i := 0; i < 30000; i++ {
+		a.Add(i)
+		b.Add(i + 20000)
+	}
+
+	sketch := a.Sketch(14)
+	assertEstimate(t, sketch.Estimate(), 30000, 0.03)
+
+	sketch.Add(30000)
+	if a.Contains(30000) {
+		t.Error("adding to a sketch should not modify the set")
+	}
+
+	assertEstimate(t, EstimateUnionCardinality(14, a, b), a.Union(b).Cardinality(), 0.03)
+}
+
+func Test_SketchMerge(t *testing.T) {
+	a := NewSketch(10)
+	if err := a.Merge(NewSketch(11)); err != ErrSketchPrecision {
+		t.Errorf("expected precision mismatch error, got %v", err)
+	}
+
+	b := NewSketch(10)
+	a.Add(1, 2, 3)
+	b.Add(3, 4)
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Estimate() != 4 {
+		t.Errorf("merged sketch should estimate 4, got %d", a.Estimate())
+	}
+}
+
+func Test_SketchBinary(t *testing.T) {
+	sketch := NewSketch(8)
+	sketch.Add("one", "two")
+
+	data, err := sketch.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Sketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Precision() != 8 || decoded.Estimate() != 2 {
+		t.Error("decoded sketch should equal the encoded one")
+	}
+	decoded.Add("two", "three")
+	if decoded.Estimate() != 3 {
+		t.Error("decoded sketch should hash like a set with default options")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:10]); err == nil {
+		t.Error("truncated encoding should not be decoded")
+	}
+}
@@ -97,6 +97,67 @@ func (set *threadSafeSet) SymmetricDifference(other Set) Set {
 	return set.threadUnsafeSet.SymmetricDifference(&o.threadUnsafeSet).ThreadSafe()
 }
 
+func (set *threadSafeSet) UnionWith(other Set) {
+	o := other.ThreadSafe()
+	unlock := lockSets(set, o)
+	defer unlock()
+	set.threadUnsafeSet.UnionWith(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) IntersectWith(other Set) {
+	o := other.ThreadSafe()
+	unlock := lockSets(set, o)
+	defer unlock()
+	set.threadUnsafeSet.IntersectWith(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) DifferenceWith(other Set) {
+	o := other.ThreadSafe()
+	unlock := lockSets(set, o)
+	defer unlock()
+	set.threadUnsafeSet.DifferenceWith(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) SymmetricDifferenceWith(other Set) {
+	o := other.ThreadSafe()
+	unlock := lockSets(set, o)
+	defer unlock()
+	set.threadUnsafeSet.SymmetricDifferenceWith(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) Jaccard(other Set) float64 {
+	o := other.ThreadSafe()
+
+	set.RLock()
+	defer set.RUnlock()
+	o.RLock()
+	defer o.RUnlock()
+
+	return set.threadUnsafeSet.Jaccard(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) Overlap(other Set) float64 {
+	o := other.ThreadSafe()
+
+	set.RLock()
+	defer set.RUnlock()
+	o.RLock()
+	defer o.RUnlock()
+
+	return set.threadUnsafeSet.Overlap(&o.threadUnsafeSet)
+}
+
+func (set *threadSafeSet) Dice(other Set) float64 {
+	o := other.ThreadSafe()
+
+	set.RLock()
+	defer set.RUnlock()
+	o.RLock()
+	defer o.RUnlock()
+
+	return set.threadUnsafeSet.Dice(&o.threadUnsafeSet)
+}
+
 func (set *threadSafeSet) Clear() {
 	set.Lock()
 	defer set.Unlock()
@@ -131,6 +192,59 @@ func (set *threadSafeSet) Each(cb func(interface{}) bool) {
 	}
 }
 
+func (set *threadSafeSet) Filter(predicate func(interface{}) bool) Set {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Filter(predicate).ThreadSafe()
+}
+
+func (set *threadSafeSet) Map(mapper func(interface{}) interface{}) Set {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Map(mapper).ThreadSafe()
+}
+
+func (set *threadSafeSet) Partition(predicate func(interface{}) bool) (matching, rest Set) {
+	set.RLock()
+	defer set.RUnlock()
+	matching, rest = set.threadUnsafeSet.Partition(predicate)
+	return matching.ThreadSafe(), rest.ThreadSafe()
+}
+
+func (set *threadSafeSet) GroupBy(key func(interface{}) interface{}) map[interface{}]Set {
+	set.RLock()
+	defer set.RUnlock()
+	groups := set.threadUnsafeSet.GroupBy(key)
+	for k, group := range groups {
+		groups[k] = group.ThreadSafe()
+	}
+	return groups
+}
+
+func (set *threadSafeSet) Reduce(reducer func(accumulator, elem interface{}) interface{}, initial interface{}) interface{} {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Reduce(reducer, initial)
+}
+
+func (set *threadSafeSet) Any(predicate func(interface{}) bool) bool {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Any(predicate)
+}
+
+func (set *threadSafeSet) All(predicate func(interface{}) bool) bool {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.All(predicate)
+}
+
+func (set *threadSafeSet) Count(predicate func(interface{}) bool) int {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Count(predicate)
+}
+
 func (set *threadSafeSet) Iter() <-chan interface{} {
 	ch := make(chan interface{})
 	go func() {
@@ -194,6 +308,12 @@ func (set *threadSafeSet) Hash() uint64 {
 	return set.threadUnsafeSet.Hash()
 }
 
+func (set *threadSafeSet) Fingerprint() Fingerprint {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Fingerprint()
+}
+
 func (set *threadSafeSet) UpdateHash() int {
 	set.Lock()
 	defer set.Unlock()
@@ -223,6 +343,12 @@ func (set *threadSafeSet) CartesianProduct(other Set) Set {
 	return set.threadUnsafeSet.CartesianProduct(&o.threadUnsafeSet).ThreadSafe()
 }
 
+func (set *threadSafeSet) Sketch(precision uint8) *Sketch {
+	set.RLock()
+	defer set.RUnlock()
+	return set.threadUnsafeSet.Sketch(precision)
+}
+
 func (set *threadSafeSet) ToSlice() []interface{} {
 	set.RLock()
 	defer set.RUnlock()
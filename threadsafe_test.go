@@ -521,3 +521,33 @@ func Test_MarshalJSON(t *testing.T) {
 		t.Errorf("Expected no difference, got: %v", expected.Difference(actual))
 	}
 }
+
+func Test_InPlaceOperationsConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewSet(), NewSet()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(2)
+		go func(v int) {
+			s.UnionWith(ss)
+			s.IntersectWith(s)
+			wg.Done()
+		}(v)
+		go func(v int) {
+			ss.UnionWith(s)
+			ss.SymmetricDifferenceWith(NewSet())
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+
+	if s.Cardinality() != N || ss.Cardinality() != N {
+		t.Errorf("unexpected cardinalities %d and %d", s.Cardinality(), ss.Cardinality())
+	}
+}
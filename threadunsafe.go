@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"github.com/OneOfOne/xxhash"
 	"github.com/gofunky/hashstructure"
+	"reflect"
 	"strings"
+	"sync"
 )
 
 type threadUnsafeSet struct {
 	options     SetOptions
-	hashState   uint64
+	fingerprint Fingerprint
+	// hash is the XOR of the element hashes, which doesn't depend on the fingerprint scheme.
+	hash        uint64
 	anyMap      map[uint64]interface{}
 	hashCache   map[interface{}]uint64
 	hashOptions *hashstructure.HashOptions
@@ -41,7 +45,8 @@ func (set *threadUnsafeSet) Add(i ...interface{}) {
 
 func (set *threadUnsafeSet) addWithHash(val interface{}, h uint64) {
 	if _, ok := set.anyMap[h]; !ok {
-		set.hashState = set.hashState ^ h
+		set.fingerprint.add(set.options.Fingerprint, h)
+		set.hash ^= h
 		set.anyMap[h] = val
 	}
 }
@@ -52,14 +57,13 @@ func (set *threadUnsafeSet) Contains(i ...interface{}) bool {
 	if argLength > cardinality {
 		return false
 	}
-	setHash := set.Hash()
-	if argLength == cardinality {
-		var inputHash uint64
+	if argLength == cardinality && !set.options.StrictEqual {
+		var inputFingerprint Fingerprint
 		for _, val := range i {
 			h := set.hashFor(val)
-			inputHash = inputHash ^ h
+			inputFingerprint.add(set.options.Fingerprint, h)
 		}
-		return inputHash == setHash
+		return inputFingerprint == set.fingerprint
 	}
 	for _, val := range i {
 		h := set.hashFor(val)
@@ -155,9 +159,99 @@ func (set *threadUnsafeSet) Difference(other Set) Set {
 }
 
 func (set *threadUnsafeSet) SymmetricDifference(other Set) Set {
-	aDiff := set.Difference(other)
-	bDiff := other.Difference(set)
-	return aDiff.Union(bDiff)
+	symmetricDifference := set.Clone().(*threadUnsafeSet)
+	symmetricDifference.SymmetricDifferenceWith(other)
+	return symmetricDifference
+}
+
+func (set *threadUnsafeSet) UnionWith(other Set) {
+	o := other.CoreSet()
+	for h, elem := range o.anyMap {
+		set.addWithHash(elem, h)
+	}
+}
+
+func (set *threadUnsafeSet) IntersectWith(other Set) {
+	o := other.CoreSet()
+	for h := range set.anyMap {
+		if !o.containsHash(h) {
+			set.removeWithHash(h)
+		}
+	}
+}
+
+func (set *threadUnsafeSet) DifferenceWith(other Set) {
+	o := other.CoreSet()
+	// loop over smaller set
+	if set.Cardinality() < o.Cardinality() {
+		for h := range set.anyMap {
+			if o.containsHash(h) {
+				set.removeWithHash(h)
+			}
+		}
+	} else {
+		for h := range o.anyMap {
+			set.removeWithHash(h)
+		}
+	}
+}
+
+func (set *threadUnsafeSet) SymmetricDifferenceWith(other Set) {
+	o := other.CoreSet()
+	for h, elem := range o.anyMap {
+		if set.containsHash(h) {
+			set.removeWithHash(h)
+		} else {
+			set.addWithHash(elem, h)
+		}
+	}
+}
+
+func (set *threadUnsafeSet) Jaccard(other Set) float64 {
+	o := other.CoreSet()
+	intersection := set.intersectionCardinality(&o)
+	union := set.Cardinality() + o.Cardinality() - intersection
+	if union == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(union)
+}
+
+func (set *threadUnsafeSet) Overlap(other Set) float64 {
+	o := other.CoreSet()
+	smaller := set.Cardinality()
+	if o.Cardinality() < smaller {
+		smaller = o.Cardinality()
+	}
+	if smaller == 0 {
+		if set.Cardinality() == o.Cardinality() {
+			return 1
+		}
+		return 0
+	}
+	return float64(set.intersectionCardinality(&o)) / float64(smaller)
+}
+
+func (set *threadUnsafeSet) Dice(other Set) float64 {
+	o := other.CoreSet()
+	total := set.Cardinality() + o.Cardinality()
+	if total == 0 {
+		return 1
+	}
+	return float64(2*set.intersectionCardinality(&o)) / float64(total)
+}
+
+func (set *threadUnsafeSet) intersectionCardinality(other *threadUnsafeSet) (count int) {
+	smaller, larger := set, other
+	if smaller.Cardinality() > larger.Cardinality() {
+		smaller, larger = larger, smaller
+	}
+	for h := range smaller.anyMap {
+		if larger.containsHash(h) {
+			count++
+		}
+	}
+	return
 }
 
 func (set *threadUnsafeSet) Clear() {
@@ -177,8 +271,11 @@ func (set *threadUnsafeSet) Remove(i ...interface{}) {
 }
 
 func (set *threadUnsafeSet) removeWithHash(hash uint64) {
-	set.hashState = set.hashState ^ hash
-	delete(set.anyMap, hash)
+	if _, ok := set.anyMap[hash]; ok {
+		set.fingerprint.remove(set.options.Fingerprint, hash)
+		set.hash ^= hash
+		delete(set.anyMap, hash)
+	}
 }
 
 func (set *threadUnsafeSet) Cardinality() int {
@@ -197,6 +294,86 @@ func (set *threadUnsafeSet) Each(cb func(interface{}) bool) {
 	}
 }
 
+func (set *threadUnsafeSet) Filter(predicate func(interface{}) bool) Set {
+	filtered := set.derived(0)
+	for h, elem := range set.anyMap {
+		if predicate(elem) {
+			filtered.addWithHash(elem, h)
+		}
+	}
+	return filtered
+}
+
+func (set *threadUnsafeSet) Map(mapper func(interface{}) interface{}) Set {
+	mapped := set.derived(set.Cardinality())
+	for _, elem := range set.anyMap {
+		mapped.Add(mapper(elem))
+	}
+	return mapped
+}
+
+func (set *threadUnsafeSet) Partition(predicate func(interface{}) bool) (matching, rest Set) {
+	matchingSet := set.derived(0)
+	restSet := set.derived(0)
+	for h, elem := range set.anyMap {
+		if predicate(elem) {
+			matchingSet.addWithHash(elem, h)
+		} else {
+			restSet.addWithHash(elem, h)
+		}
+	}
+	return matchingSet, restSet
+}
+
+func (set *threadUnsafeSet) GroupBy(key func(interface{}) interface{}) map[interface{}]Set {
+	groups := make(map[interface{}]Set)
+	for h, elem := range set.anyMap {
+		k := key(elem)
+		group, ok := groups[k]
+		if !ok {
+			group = set.derived(0)
+			groups[k] = group
+		}
+		group.(*threadUnsafeSet).addWithHash(elem, h)
+	}
+	return groups
+}
+
+func (set *threadUnsafeSet) Reduce(reducer func(accumulator, elem interface{}) interface{}, initial interface{}) interface{} {
+	accumulator := initial
+	for _, elem := range set.anyMap {
+		accumulator = reducer(accumulator, elem)
+	}
+	return accumulator
+}
+
+func (set *threadUnsafeSet) Any(predicate func(interface{}) bool) bool {
+	for _, elem := range set.anyMap {
+		if predicate(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *threadUnsafeSet) All(predicate func(interface{}) bool) bool {
+	for _, elem := range set.anyMap {
+		if !predicate(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *threadUnsafeSet) Count(predicate func(interface{}) bool) (count int) {
+	for _, elem := range set.anyMap {
+		if predicate(elem) {
+			count++
+		}
+	}
+	return
+}
+
 func (set *threadUnsafeSet) Iter() <-chan interface{} {
 	ch := make(chan interface{})
 	go func() {
@@ -231,7 +408,21 @@ func (set *threadUnsafeSet) Equal(other Set) bool {
 	if set.Cardinality() != other.Cardinality() {
 		return false
 	}
-	return set.Hash() == other.Hash()
+	otherCore := other.CoreSet()
+	if set.options.Fingerprint == otherCore.options.Fingerprint {
+		if set.fingerprint != otherCore.fingerprint {
+			return false
+		}
+		if !set.options.StrictEqual {
+			return true
+		}
+	}
+	for h := range set.anyMap {
+		if !otherCore.containsHash(h) {
+			return false
+		}
+	}
+	return true
 }
 
 func (set *threadUnsafeSet) Clone() Set {
@@ -243,7 +434,8 @@ func (set *threadUnsafeSet) Clone() Set {
 		options:     set.options,
 		hashCache:   set.hashCache,
 		anyMap:      nextAny,
-		hashState:   set.hashState,
+		fingerprint: set.fingerprint,
+		hash:        set.hash,
 		hashOptions: set.hashOptions,
 	}
 }
@@ -316,6 +508,14 @@ func (set *threadUnsafeSet) CartesianProduct(other Set) Set {
 	return cartProduct
 }
 
+func (set *threadUnsafeSet) Sketch(precision uint8) *Sketch {
+	sketch := newSketch(precision, set.derived(0))
+	for h := range set.anyMap {
+		sketch.AddHash(h)
+	}
+	return sketch
+}
+
 func (set *threadUnsafeSet) ToSlice() (keys []interface{}) {
 	keys = make([]interface{}, 0, set.Cardinality())
 	for _, elem := range set.anyMap {
@@ -332,17 +532,32 @@ func (set *threadUnsafeSet) ThreadSafe() *threadSafeSet {
 	return &threadSafeSet{threadUnsafeSet: *set}
 }
 
+// Hash mixes the XOR of the element hashes so that nested sets, e.g., the different partitions of the same elements,
+// don't cancel each other out in the XOR of their parent set.
+// Since equal sets must have equal hashes, the hash doesn't depend on the fingerprint scheme.
 func (set threadUnsafeSet) Hash() uint64 {
-	return set.hashState
+	return mix64(set.hash)
+}
+
+func (set *threadUnsafeSet) Fingerprint() Fingerprint {
+	return set.fingerprint
 }
 
 func (set *threadUnsafeSet) UpdateHash() (updated int) {
+	return set.updateHash(nil)
+}
+
+// updateHash recalculates the hashes of all elements and reports every changed hash to the given callback.
+func (set *threadUnsafeSet) updateHash(changed func(previous, current uint64)) (updated int) {
 	set.hashCache = make(map[interface{}]uint64)
 	for hash, elem := range set.anyMap {
 		h := set.hashFor(elem)
 		if hash != h {
 			set.removeWithHash(hash)
 			set.addWithHash(elem, h)
+			if changed != nil {
+				changed(hash, h)
+			}
 			updated += 1
 		}
 	}
@@ -395,8 +610,18 @@ func (set *threadUnsafeSet) emptySet() *threadUnsafeSet {
 	}
 }
 
+func (set *threadUnsafeSet) sizedEmptySet(size int) *threadUnsafeSet {
+	return &threadUnsafeSet{
+		options:     set.options,
+		hashCache:   set.hashCache,
+		anyMap:      make(map[uint64]interface{}, size),
+		hashOptions: set.hashOptions,
+	}
+}
+
 func (set *threadUnsafeSet) hashFor(i interface{}) uint64 {
-	if set.options.Cache {
+	cache := set.options.Cache && cacheable(i)
+	if cache {
 		if el, ok := set.hashCache[i]; ok {
 			return el
 		}
@@ -405,8 +630,113 @@ func (set *threadUnsafeSet) hashFor(i interface{}) uint64 {
 	if err != nil {
 		panic(err)
 	}
-	if set.options.Cache {
+	if cache {
 		set.hashCache[i] = h
 	}
 	return h
 }
+
+// cacheability classifies whether the elements of a type can be used as keys of the hash cache.
+type cacheability uint8
+
+const (
+	// uncacheableType elements are never cached, e.g., slices or tuples.
+	uncacheableType cacheability = iota
+	// cacheableType elements are always cached.
+	cacheableType
+	// cacheableValue elements are of a comparable type that holds interfaces.
+	// They are only cached if the values of the interfaces are comparable.
+	cacheableValue
+)
+
+// cacheabilities holds the cacheability of every struct and array type that was checked before.
+var cacheabilities sync.Map
+
+// cacheable determines whether the given element can be used as a map key without panicking.
+// Builtin types are matched directly and other basic types by their kind only, which keeps the common case fast.
+func cacheable(i interface{}) bool {
+	switch i.(type) {
+	case nil, bool, string, int, int64, uint64, float64:
+		return true
+	}
+	t := reflect.TypeOf(i)
+	if k := t.Kind(); k <= reflect.Complex128 || k == reflect.String || k == reflect.Ptr {
+		return true
+	}
+	return cacheableComposite(t, i)
+}
+
+// cacheableComposite determines whether the given element of a composite type can be used as a map key.
+// The cacheability of struct and array types is only determined once.
+func cacheableComposite(t reflect.Type, i interface{}) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array:
+		c, ok := cacheabilities.Load(t)
+		if !ok {
+			c, _ = cacheabilities.LoadOrStore(t, typeCacheability(t))
+		}
+		switch c.(cacheability) {
+		case cacheableType:
+			return true
+		case cacheableValue:
+			return comparableValue(reflect.ValueOf(i))
+		default:
+			return false
+		}
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	default:
+		return true
+	}
+}
+
+// typeCacheability determines the cacheability of the given struct or array type.
+func typeCacheability(t reflect.Type) cacheability {
+	if !t.Comparable() {
+		return uncacheableType
+	}
+	if holdsInterface(t) {
+		return cacheableValue
+	}
+	return cacheableType
+}
+
+// holdsInterface determines whether the given type has interface fields or elements.
+func holdsInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return holdsInterface(t.Elem())
+	case reflect.Struct:
+		for n := 0; n < t.NumField(); n++ {
+			if holdsInterface(t.Field(n).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// comparableValue determines whether the given value and the values of all its interfaces are comparable.
+func comparableValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Interface:
+		return v.IsNil() || comparableValue(v.Elem())
+	case reflect.Array:
+		for n := 0; n < v.Len(); n++ {
+			if !comparableValue(v.Index(n)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for n := 0; n < v.NumField(); n++ {
+			if !comparableValue(v.Field(n)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -47,3 +47,24 @@ func Test_threadUnsafeSet_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_threadUnsafeSet_hashCache(t *testing.T) {
+	type holder struct {
+		Value interface{}
+	}
+	set := NewUnsafeSet(1, NewTuple(1, 2), holder{Value: []int{1}}).(*threadUnsafeSet)
+	if !set.Contains(1, NewTuple(1, 2), holder{Value: []int{1}}) {
+		t.Errorf("all elements should be found, got %v", set)
+	}
+	if len(set.hashCache) != 1 {
+		t.Errorf("only the comparable element should be cached, got %v", set.hashCache)
+	}
+
+	set = NewUnsafeSet(OrderedPair{First: 1, Second: "one"}, OrderedPair{First: []int{1}, Second: 1}).(*threadUnsafeSet)
+	if !set.Contains(OrderedPair{First: []int{1}, Second: 1}) {
+		t.Errorf("all elements should be found, got %v", set)
+	}
+	if len(set.hashCache) != 1 {
+		t.Errorf("only the pair of comparable values should be cached, got %v", set.hashCache)
+	}
+}
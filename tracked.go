@@ -0,0 +1,198 @@
+package mapset
+
+// tracker maintains additional state per element of a trackedSet, such as expiries, usages, or index keys.
+type tracker interface {
+	// begin prepares an operation while the set is unlocked, e.g., by removing expired elements.
+	begin()
+	// end finishes an operation while the set is unlocked, e.g., by notifying about evicted elements.
+	end()
+	// admit determines whether the given elements may be added. It expects the set to be locked.
+	admit(elements map[uint64]interface{}) error
+	// track adds an element or uses one that is already in the set. It expects the set to be locked.
+	track(val interface{}, h uint64)
+	// untrack removes an element and its state. It expects the set to be locked.
+	untrack(h uint64)
+	// compact drops the state of elements that were removed by the underlying set. It expects the set to be locked.
+	compact()
+	// cloneWith creates a copy of the tracker around the given copy of the elements. It expects the set to be read-locked.
+	cloneWith(core *threadUnsafeSet) Set
+}
+
+// trackedSet is a thread-safe set that implements the mutating operations on top of the hooks of a tracker,
+// so that the state of the tracker can't diverge from the elements.
+type trackedSet struct {
+	threadSafeSet
+	tracker tracker
+}
+
+// hashed provides the given elements by their hashes. It expects the set to be locked.
+func (set *trackedSet) hashed(i []interface{}) (hashes []uint64, elements map[uint64]interface{}) {
+	hashes = make([]uint64, len(i))
+	elements = make(map[uint64]interface{}, len(i))
+	for n, val := range i {
+		hashes[n] = set.hashFor(val)
+		elements[hashes[n]] = val
+	}
+	return
+}
+
+// insert adds the given elements in order unless the tracker rejects them.
+func (set *trackedSet) insert(i []interface{}) error {
+	set.tracker.begin()
+	set.Lock()
+	hashes, elements := set.hashed(i)
+	err := set.tracker.admit(elements)
+	if err == nil {
+		for n, h := range hashes {
+			set.tracker.track(i[n], h)
+		}
+	}
+	set.Unlock()
+	set.tracker.end()
+	return err
+}
+
+// trackAll adds the given elements if the tracker admits them. It expects the set to be locked.
+func (set *trackedSet) trackAll(elements map[uint64]interface{}) error {
+	if err := set.tracker.admit(elements); err != nil {
+		return err
+	}
+	for h, elem := range elements {
+		set.tracker.track(elem, h)
+	}
+	return nil
+}
+
+// Add panics if the tracker rejects the elements.
+func (set *trackedSet) Add(i ...interface{}) {
+	if err := set.insert(i); err != nil {
+		panic(err)
+	}
+}
+
+func (set *trackedSet) Remove(i ...interface{}) {
+	set.tracker.begin()
+	set.Lock()
+	for _, val := range i {
+		set.tracker.untrack(set.hashFor(val))
+	}
+	set.Unlock()
+	set.tracker.end()
+}
+
+func (set *trackedSet) Clear() {
+	set.Lock()
+	defer set.Unlock()
+	set.threadUnsafeSet.Clear()
+	set.tracker.compact()
+}
+
+func (set *trackedSet) Pop() interface{} {
+	set.tracker.begin()
+	set.Lock()
+	var popped interface{}
+	for h, elem := range set.threadUnsafeSet.anyMap {
+		set.tracker.untrack(h)
+		popped = elem
+		break
+	}
+	set.Unlock()
+	set.tracker.end()
+	return popped
+}
+
+// UnionWith panics if the tracker rejects the elements of the other set.
+func (set *trackedSet) UnionWith(other Set) {
+	set.tracker.begin()
+	o := other.ThreadSafe()
+	unlock := lockSets(&set.threadSafeSet, o)
+	missing := make(map[uint64]interface{})
+	for h, elem := range o.threadUnsafeSet.anyMap {
+		if !set.threadUnsafeSet.containsHash(h) {
+			missing[h] = elem
+		}
+	}
+	err := set.trackAll(missing)
+	unlock()
+	set.tracker.end()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (set *trackedSet) IntersectWith(other Set) {
+	set.tracker.begin()
+	o := other.ThreadSafe()
+	unlock := lockSets(&set.threadSafeSet, o)
+	set.threadUnsafeSet.IntersectWith(&o.threadUnsafeSet)
+	set.tracker.compact()
+	unlock()
+	set.tracker.end()
+}
+
+func (set *trackedSet) DifferenceWith(other Set) {
+	set.tracker.begin()
+	o := other.ThreadSafe()
+	unlock := lockSets(&set.threadSafeSet, o)
+	set.threadUnsafeSet.DifferenceWith(&o.threadUnsafeSet)
+	set.tracker.compact()
+	unlock()
+	set.tracker.end()
+}
+
+// SymmetricDifferenceWith removes the common elements first and then adds the others.
+// If the tracker rejects the added elements, it restores the removed ones and panics.
+func (set *trackedSet) SymmetricDifferenceWith(other Set) {
+	set.tracker.begin()
+	o := other.ThreadSafe()
+	unlock := lockSets(&set.threadSafeSet, o)
+	if o == &set.threadSafeSet {
+		set.threadUnsafeSet.Clear()
+		set.tracker.compact()
+		unlock()
+		return
+	}
+	added := make(map[uint64]interface{})
+	removed := make(map[uint64]interface{})
+	for h, elem := range o.threadUnsafeSet.anyMap {
+		if set.threadUnsafeSet.containsHash(h) {
+			removed[h] = elem
+			set.tracker.untrack(h)
+		} else {
+			added[h] = elem
+		}
+	}
+	err := set.trackAll(added)
+	if err != nil {
+		// the removed elements were admitted before
+		_ = set.trackAll(removed)
+	}
+	unlock()
+	set.tracker.end()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UnmarshalJSON adds the elements of a JSON array. It adds none of them if the tracker rejects them.
+func (set *trackedSet) UnmarshalJSON(p []byte) error {
+	decoded := derivedFrom(set, 0)
+	if err := decoded.UnmarshalJSON(p); err != nil {
+		return err
+	}
+	set.tracker.begin()
+	set.Lock()
+	err := set.trackAll(decoded.anyMap)
+	set.Unlock()
+	set.tracker.end()
+	return err
+}
+
+func (set *trackedSet) Clone() Set {
+	set.tracker.begin()
+	set.RLock()
+	clone := set.tracker.cloneWith(set.threadUnsafeSet.Clone().(*threadUnsafeSet))
+	set.RUnlock()
+	set.tracker.end()
+	return clone
+}
@@ -0,0 +1,468 @@
+package mapset
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time. Replace it to test expiring sets deterministically.
+type Clock interface {
+	// Now provides the current time.
+	Now() time.Time
+}
+
+// systemClock provides the current time of the system.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ExpiringSet is a set whose elements expire after a time to live (TTL).
+// Expired elements are removed lazily whenever the set is accessed, and optionally by a background janitor.
+// Cardinality, iteration, and all set operations exclude expired elements.
+// Operations on an ExpiringSet are thread-safe.
+type ExpiringSet interface {
+	Set
+
+	// AddWithTTL adds the given elements that expire after the given duration.
+	// Elements that are already in the set expire after the given duration from now on.
+	// A duration of zero or less makes the elements never expire.
+	AddWithTTL(ttl time.Duration, i ...interface{})
+
+	// Expiry provides the time at which the given element expires.
+	// The time is zero if the element never expires. If the element is not in the set, ok is false.
+	Expiry(i interface{}) (at time.Time, ok bool)
+
+	// Close stops the background janitor. The set remains usable and still removes expired elements lazily.
+	Close()
+}
+
+// TTLOptions contain options that affect the construction of an ExpiringSet.
+type TTLOptions struct {
+	// SetOptions affect the underlying set. Expiring sets are always thread-safe, the Unsafe option is ignored.
+	SetOptions
+	// TTL is the time to live of elements that are added by Add. Zero makes them never expire.
+	TTL time.Duration
+	// Clock overrides the system clock.
+	Clock Clock
+	// JanitorInterval enables a background janitor that removes expired elements in the given interval.
+	// Call Close to stop it.
+	JanitorInterval time.Duration
+}
+
+// NewExpiringSet creates a set whose elements expire after the given time to live and that contains the given elements.
+// Operations on the resulting set are thread-safe.
+func NewExpiringSet(ttl time.Duration, elements ...interface{}) ExpiringSet {
+	options := TTLOptions{
+		SetOptions: SetOptions{Cache: true},
+		TTL:        ttl,
+	}
+	return options.New(elements...)
+}
+
+// New creates a new expiring set with the given options.
+func (o TTLOptions) New(elements ...interface{}) ExpiringSet {
+	set := &ttlSet{
+		trackedSet: trackedSet{threadSafeSet: o.SetOptions.newThreadSafeSet()},
+		ttl:        o.TTL,
+		clock:      o.Clock,
+		expiries:   make(map[uint64]*expiration),
+		stop:       make(chan struct{}),
+	}
+	set.tracker = set
+	if set.clock == nil {
+		set.clock = systemClock{}
+	}
+	set.Add(elements...)
+	if o.JanitorInterval > 0 {
+		go set.janitor(o.JanitorInterval)
+	}
+	return set
+}
+
+type ttlSet struct {
+	trackedSet
+	ttl       time.Duration
+	clock     Clock
+	expiries  map[uint64]*expiration
+	queue     expiryQueue
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// expiration tracks the expiry of an element of an expiring set.
+type expiration struct {
+	hash uint64
+	at   time.Time
+	// index is the position in the expiry queue
+	index int
+}
+
+// expiryQueue is a min-heap of expirations whose first element expires next.
+type expiryQueue []*expiration
+
+func (q expiryQueue) Len() int           { return len(q) }
+func (q expiryQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q expiryQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *expiryQueue) Push(x interface{}) {
+	e := x.(*expiration)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *expiryQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+func (set *ttlSet) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-set.stop:
+			return
+		case <-ticker.C:
+			set.purge()
+		}
+	}
+}
+
+// purge removes all expired elements.
+func (set *ttlSet) purge() {
+	now := set.clock.Now()
+	set.RLock()
+	expired := len(set.queue) > 0 && !set.queue[0].at.After(now)
+	set.RUnlock()
+	if !expired {
+		return
+	}
+
+	set.Lock()
+	defer set.Unlock()
+	for len(set.queue) > 0 && !set.queue[0].at.After(now) {
+		set.removeHash(set.queue[0].hash)
+	}
+}
+
+// addHash adds or refreshes an element with the given time to live. It expects the set to be locked.
+func (set *ttlSet) addHash(val interface{}, h uint64, ttl time.Duration, now time.Time) {
+	set.threadUnsafeSet.addWithHash(val, h)
+	if ttl <= 0 {
+		set.forget(h)
+		return
+	}
+	if e, ok := set.expiries[h]; ok {
+		e.at = now.Add(ttl)
+		heap.Fix(&set.queue, e.index)
+		return
+	}
+	e := &expiration{hash: h, at: now.Add(ttl)}
+	heap.Push(&set.queue, e)
+	set.expiries[h] = e
+}
+
+// removeHash removes an element and its expiry. It expects the set to be locked.
+func (set *ttlSet) removeHash(h uint64) {
+	set.threadUnsafeSet.removeWithHash(h)
+	set.forget(h)
+}
+
+// forget drops the expiry of an element. It expects the set to be locked.
+func (set *ttlSet) forget(h uint64) {
+	if e, ok := set.expiries[h]; ok {
+		heap.Remove(&set.queue, e.index)
+		delete(set.expiries, h)
+	}
+}
+
+// compact drops the expiries of elements that were removed by the underlying set. It expects the set to be locked.
+func (set *ttlSet) compact() {
+	for h := range set.expiries {
+		if !set.threadUnsafeSet.containsHash(h) {
+			set.forget(h)
+		}
+	}
+}
+
+func (set *ttlSet) begin() {
+	set.purge()
+}
+
+func (set *ttlSet) end() {}
+
+func (set *ttlSet) admit(map[uint64]interface{}) error {
+	return nil
+}
+
+func (set *ttlSet) track(val interface{}, h uint64) {
+	set.addHash(val, h, set.ttl, set.clock.Now())
+}
+
+func (set *ttlSet) untrack(h uint64) {
+	set.removeHash(h)
+}
+
+func (set *ttlSet) AddWithTTL(ttl time.Duration, i ...interface{}) {
+	now := set.clock.Now()
+	set.purge()
+	set.Lock()
+	defer set.Unlock()
+	for _, val := range i {
+		set.addHash(val, set.hashFor(val), ttl, now)
+	}
+}
+
+func (set *ttlSet) Expiry(i interface{}) (at time.Time, ok bool) {
+	set.purge()
+	set.Lock()
+	defer set.Unlock()
+	h := set.hashFor(i)
+	if !set.threadUnsafeSet.containsHash(h) {
+		return time.Time{}, false
+	}
+	if e, ok := set.expiries[h]; ok {
+		return e.at, true
+	}
+	return time.Time{}, true
+}
+
+func (set *ttlSet) Close() {
+	set.closeOnce.Do(func() {
+		close(set.stop)
+	})
+}
+
+func (set *ttlSet) UpdateHash() int {
+	set.purge()
+	set.Lock()
+	defer set.Unlock()
+	moved := make(map[uint64]*expiration)
+	updated := set.threadUnsafeSet.updateHash(func(previous, current uint64) {
+		if e, ok := set.expiries[previous]; ok {
+			delete(set.expiries, previous)
+			if _, ok := moved[current]; ok {
+				heap.Remove(&set.queue, e.index)
+				return
+			}
+			moved[current] = e
+		}
+	})
+	for h, e := range moved {
+		if _, ok := set.expiries[h]; ok {
+			// the element collided with another one and was dropped by the underlying set
+			heap.Remove(&set.queue, e.index)
+			continue
+		}
+		e.hash = h
+		set.expiries[h] = e
+	}
+	set.compact()
+	return updated
+}
+
+func (set *ttlSet) cloneWith(core *threadUnsafeSet) Set {
+	clone := &ttlSet{
+		trackedSet: trackedSet{threadSafeSet: threadSafeSet{threadUnsafeSet: *core}},
+		ttl:        set.ttl,
+		clock:      set.clock,
+		expiries:   make(map[uint64]*expiration, len(set.expiries)),
+		queue:      make(expiryQueue, len(set.queue)),
+		stop:       make(chan struct{}),
+	}
+	clone.tracker = clone
+	for n, e := range set.queue {
+		copied := *e
+		clone.queue[n] = &copied
+		clone.expiries[e.hash] = &copied
+	}
+	return clone
+}
+
+func (set *ttlSet) Hash() uint64 {
+	set.purge()
+	return set.threadSafeSet.Hash()
+}
+
+func (set *ttlSet) Fingerprint() Fingerprint {
+	set.purge()
+	return set.threadSafeSet.Fingerprint()
+}
+
+func (set *ttlSet) Cardinality() int {
+	set.purge()
+	return set.threadSafeSet.Cardinality()
+}
+
+func (set *ttlSet) Empty() bool {
+	set.purge()
+	return set.threadSafeSet.Empty()
+}
+
+func (set *ttlSet) Contains(i ...interface{}) bool {
+	set.purge()
+	return set.threadSafeSet.Contains(i...)
+}
+
+func (set *ttlSet) Jaccard(other Set) float64 {
+	set.purge()
+	return set.threadSafeSet.Jaccard(other)
+}
+
+func (set *ttlSet) Overlap(other Set) float64 {
+	set.purge()
+	return set.threadSafeSet.Overlap(other)
+}
+
+func (set *ttlSet) Dice(other Set) float64 {
+	set.purge()
+	return set.threadSafeSet.Dice(other)
+}
+
+func (set *ttlSet) Difference(other Set) Set {
+	set.purge()
+	return set.threadSafeSet.Difference(other)
+}
+
+func (set *ttlSet) Equal(other Set) bool {
+	set.purge()
+	return set.threadSafeSet.Equal(other)
+}
+
+func (set *ttlSet) Intersect(other Set) Set {
+	set.purge()
+	return set.threadSafeSet.Intersect(other)
+}
+
+func (set *ttlSet) IsProperSubset(other Set) bool {
+	set.purge()
+	return set.threadSafeSet.IsProperSubset(other)
+}
+
+func (set *ttlSet) IsProperSuperset(other Set) bool {
+	return other.IsProperSubset(set)
+}
+
+func (set *ttlSet) IsSubset(other Set) bool {
+	set.purge()
+	return set.threadSafeSet.IsSubset(other)
+}
+
+func (set *ttlSet) IsSuperset(other Set) bool {
+	return other.IsSubset(set)
+}
+
+func (set *ttlSet) Each(cb func(interface{}) bool) {
+	set.purge()
+	set.threadSafeSet.Each(cb)
+}
+
+func (set *ttlSet) Filter(predicate func(interface{}) bool) Set {
+	set.purge()
+	return set.threadSafeSet.Filter(predicate)
+}
+
+func (set *ttlSet) Map(mapper func(interface{}) interface{}) Set {
+	set.purge()
+	return set.threadSafeSet.Map(mapper)
+}
+
+func (set *ttlSet) Partition(predicate func(interface{}) bool) (matching, rest Set) {
+	set.purge()
+	return set.threadSafeSet.Partition(predicate)
+}
+
+func (set *ttlSet) GroupBy(key func(interface{}) interface{}) map[interface{}]Set {
+	set.purge()
+	return set.threadSafeSet.GroupBy(key)
+}
+
+func (set *ttlSet) Reduce(reducer func(accumulator, elem interface{}) interface{}, initial interface{}) interface{} {
+	set.purge()
+	return set.threadSafeSet.Reduce(reducer, initial)
+}
+
+func (set *ttlSet) Any(predicate func(interface{}) bool) bool {
+	set.purge()
+	return set.threadSafeSet.Any(predicate)
+}
+
+func (set *ttlSet) All(predicate func(interface{}) bool) bool {
+	set.purge()
+	return set.threadSafeSet.All(predicate)
+}
+
+func (set *ttlSet) Count(predicate func(interface{}) bool) int {
+	set.purge()
+	return set.threadSafeSet.Count(predicate)
+}
+
+func (set *ttlSet) Iter() <-chan interface{} {
+	set.purge()
+	return set.threadSafeSet.Iter()
+}
+
+func (set *ttlSet) Iterator() *Iterator {
+	set.purge()
+	return set.threadSafeSet.Iterator()
+}
+
+func (set *ttlSet) String() string {
+	set.purge()
+	return set.threadSafeSet.String()
+}
+
+func (set *ttlSet) SymmetricDifference(other Set) Set {
+	set.purge()
+	return set.threadSafeSet.SymmetricDifference(other)
+}
+
+func (set *ttlSet) Union(other Set) Set {
+	set.purge()
+	return set.threadSafeSet.Union(other)
+}
+
+func (set *ttlSet) PowerSet() Set {
+	set.purge()
+	return set.threadSafeSet.PowerSet()
+}
+
+func (set *ttlSet) CartesianProduct(other Set) Set {
+	set.purge()
+	return set.threadSafeSet.CartesianProduct(other)
+}
+
+func (set *ttlSet) Sketch(precision uint8) *Sketch {
+	set.purge()
+	return set.threadSafeSet.Sketch(precision)
+}
+
+func (set *ttlSet) ToSlice() []interface{} {
+	set.purge()
+	return set.threadSafeSet.ToSlice()
+}
+
+func (set *ttlSet) CoreSet() threadUnsafeSet {
+	set.purge()
+	return set.threadSafeSet.CoreSet()
+}
+
+func (set *ttlSet) ThreadSafe() *threadSafeSet {
+	set.purge()
+	return &set.threadSafeSet
+}
+
+func (set *ttlSet) MarshalJSON() ([]byte, error) {
+	set.purge()
+	return set.threadSafeSet.MarshalJSON()
+}
@@ -0,0 +1,193 @@
+package mapset
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestExpiringSet(clock Clock, elements ...interface{}) ExpiringSet {
+	options := TTLOptions{
+		SetOptions: SetOptions{Cache: true},
+		TTL:        time.Minute,
+		Clock:      clock,
+	}
+	return options.New(elements...)
+}
+
+func Test_ExpiringSetExpiry(t *testing.T) {
+	clock := newFakeClock()
+	a := newTestExpiringSet(clock, 1, 2)
+	clock.Advance(30 * time.Second)
+	a.AddWithTTL(2*time.Minute, 3)
+	a.AddWithTTL(0, 4)
+
+	if !a.Contains(1, 2, 3, 4) || a.Cardinality() != 4 {
+		t.Errorf("set should contain all elements before they expire, got %v", a)
+	}
+
+	clock.Advance(30 * time.Second)
+	if a.Contains(1) || a.Contains(2) {
+		t.Error("elements added by Add should expire after the default TTL")
+	}
+	if a.Cardinality() != 2 || !a.Equal(NewSet(3, 4)) {
+		t.Errorf("expected {3, 4}, got %v", a)
+	}
+	if slice := a.ToSlice(); len(slice) != 2 {
+		t.Errorf("iteration should exclude expired elements, got %v", slice)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if !a.Equal(NewSet(4)) {
+		t.Errorf("only the element without TTL should remain, got %v", a)
+	}
+	if at, ok := a.Expiry(4); !ok || !at.IsZero() {
+		t.Errorf("element without TTL should never expire, got %v, %v", at, ok)
+	}
+	if _, ok := a.Expiry(1); ok {
+		t.Error("expired element should have no expiry")
+	}
+}
+
+func Test_ExpiringSetRefresh(t *testing.T) {
+	clock := newFakeClock()
+	a := newTestExpiringSet(clock, "foo")
+	start := clock.Now()
+
+	clock.Advance(45 * time.Second)
+	a.Add("foo")
+	if at, _ := a.Expiry("foo"); !at.Equal(start.Add(45*time.Second + time.Minute)) {
+		t.Errorf("adding an element again should refresh its expiry, got %v", at)
+	}
+
+	clock.Advance(45 * time.Second)
+	if !a.Contains("foo") {
+		t.Error("refreshed element should not expire with its previous expiry")
+	}
+
+	a.Remove("foo")
+	a.AddWithTTL(0, "foo")
+	clock.Advance(time.Hour)
+	if !a.Contains("foo") {
+		t.Error("element that was added again without TTL should not expire")
+	}
+}
+
+func Test_ExpiringSetQueueBounded(t *testing.T) {
+	a := newTestExpiringSet(newFakeClock()).(*ttlSet)
+	for i := 0; i < 1000; i++ {
+		a.Add("same")
+		a.Add("same")
+		a.Remove("same")
+	}
+	a.Add("same", "other")
+	if len(a.queue) != 2 || len(a.expiries) != 2 {
+		t.Errorf("expected one queue entry per element, got %d entries", len(a.queue))
+	}
+}
+
+func Test_ExpiringSetUpdateHash(t *testing.T) {
+	clock := newFakeClock()
+	nested := NewSet("foo")
+	a := newTestExpiringSet(clock, nested)
+
+	nested.Add("bar")
+	if updated := a.UpdateHash(); updated != 1 {
+		t.Fatalf("expected 1 updated hash, got %d", updated)
+	}
+	if _, ok := a.Expiry(NewSet("foo", "bar")); !ok {
+		t.Fatal("element should be found by its updated hash")
+	}
+
+	clock.Advance(time.Minute)
+	if !a.Empty() {
+		t.Errorf("element should still expire after its hash was updated, got %v", a)
+	}
+}
+
+func Test_ExpiringSetOperations(t *testing.T) {
+	clock := newFakeClock()
+	a := newTestExpiringSet(clock, 1, 2, 3)
+	b := NewSet(2, 3, 4)
+
+	a.UnionWith(b)
+	if !a.Equal(NewSet(1, 2, 3, 4)) {
+		t.Errorf("expected {1, 2, 3, 4}, got %v", a)
+	}
+	if !b.Union(a).Equal(NewSet(1, 2, 3, 4)) || !b.IsSubset(a) {
+		t.Error("expiring set should interoperate with ordinary sets")
+	}
+
+	clock.Advance(time.Minute)
+	a.Add(5)
+	if !a.Equal(NewSet(5)) {
+		t.Errorf("elements added by UnionWith should expire, got %v", a)
+	}
+	if !b.Union(a).Equal(NewSet(2, 3, 4, 5)) || a.IsSubset(b) {
+		t.Error("ordinary sets should not see expired elements")
+	}
+
+	a.SymmetricDifferenceWith(NewSet(5, 6))
+	a.DifferenceWith(NewSet(7))
+	if !a.Equal(NewSet(6)) {
+		t.Errorf("expected {6}, got %v", a)
+	}
+
+	clone := a.Clone()
+	clock.Advance(time.Minute)
+	if !clone.Empty() {
+		t.Errorf("clone should keep the expiries, got %v", clone)
+	}
+}
+
+func Test_ExpiringSetJanitor(t *testing.T) {
+	clock := newFakeClock()
+	options := TTLOptions{
+		SetOptions:      SetOptions{Cache: true},
+		TTL:             time.Minute,
+		Clock:           clock,
+		JanitorInterval: time.Millisecond,
+	}
+	a := options.New(1, 2, 3).(*ttlSet)
+	defer a.Close()
+
+	clock.Advance(time.Minute)
+	deadline := time.Now().Add(time.Second)
+	for {
+		a.RLock()
+		remaining := a.threadUnsafeSet.Cardinality()
+		a.RUnlock()
+		if remaining == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("janitor should remove expired elements, %d remaining", remaining)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	a.Close()
+	a.Close()
+}
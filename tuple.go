@@ -0,0 +1,80 @@
+package mapset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Tuple represents an n-tuple of values.
+// Tuples with two values hash like an OrderedPair with the same values.
+type Tuple struct {
+	elements []interface{}
+}
+
+// NewTuple creates a tuple of the given values.
+func NewTuple(elements ...interface{}) Tuple {
+	return Tuple{elements: append([]interface{}(nil), elements...)}
+}
+
+// Len determines the number of values of the tuple.
+func (t Tuple) Len() int {
+	return len(t.elements)
+}
+
+// At provides the value at the given position of the tuple. It panics if the position is out of range.
+func (t Tuple) At(i int) interface{} {
+	return t.elements[i]
+}
+
+// Slice provides a copy of the values of the tuple.
+func (t Tuple) Slice() []interface{} {
+	return append([]interface{}(nil), t.elements...)
+}
+
+// Hash calculates the hash of the tuple. It always uses the default hasher, regardless of SetOptions.Hasher.
+func (t Tuple) Hash() uint64 {
+	return hashSequence(t.elements...)
+}
+
+// String outputs a tuple in the form "(A, B, C)".
+func (t Tuple) String() string {
+	items := make([]string, 0, len(t.elements))
+	for _, elem := range t.elements {
+		items = append(items, fmt.Sprintf("%v", elem))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(items, ", "))
+}
+
+// Equal determines if this tuple equals the given tuple. The values are compared like those of OrderedPair.Equal.
+func (t Tuple) Equal(other Tuple) bool {
+	return t.Len() == other.Len() && t.Hash() == other.Hash()
+}
+
+// Project provides a tuple of the values at the given positions. It panics if a position is out of range.
+func (t Tuple) Project(indices ...int) Tuple {
+	elements := make([]interface{}, len(indices))
+	for n, i := range indices {
+		elements[n] = t.elements[i]
+	}
+	return Tuple{elements: elements}
+}
+
+// MarshalJSON creates a JSON array with the values of the tuple.
+func (t Tuple) MarshalJSON() ([]byte, error) {
+	if t.elements == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t.elements)
+}
+
+// UnmarshalJSON recreates a tuple from a JSON array.
+// Numbers are decoded as json.Number.
+func (t *Tuple) UnmarshalJSON(b []byte) error {
+	values, err := decodeJSONArray(b)
+	if err != nil {
+		return err
+	}
+	t.elements = values
+	return nil
+}
@@ -0,0 +1,69 @@
+package mapset
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_Tuple(t *testing.T) {
+	tuple := NewTuple(1, "two", 3.0)
+
+	if tuple.Len() != 3 || tuple.At(1) != "two" {
+		t.Errorf("unexpected tuple %v", tuple)
+	}
+	if tuple.String() != "(1, two, 3)" {
+		t.Errorf("unexpected tuple string %v", tuple.String())
+	}
+	if NewTuple().String() != "()" {
+		t.Errorf("unexpected empty tuple string %v", NewTuple().String())
+	}
+}
+
+func Test_TupleHash(t *testing.T) {
+	if NewTuple(1, "a").Hash() != NewTuple(1, "a").Hash() {
+		t.Error("equal tuples should have equal hashes")
+	}
+	if NewTuple(1, "a").Hash() == NewTuple("a", 1).Hash() {
+		t.Error("the hash of a tuple should depend on the order of its values")
+	}
+	if NewTuple(1, 2).Hash() == NewTuple(1, 2, nil).Hash() {
+		t.Error("the hash of a tuple should depend on its arity")
+	}
+	if NewTuple(1, "a").Hash() != (OrderedPair{First: 1, Second: "a"}).Hash() {
+		t.Error("a tuple of two values should hash like an ordered pair")
+	}
+
+	set := NewSet(NewTuple(1, []int{2, 3}))
+	if !set.Contains(OrderedPair{First: 1, Second: []int{2, 3}}) {
+		t.Error("a set of tuples should contain an equal ordered pair")
+	}
+}
+
+func Test_TupleEqualProject(t *testing.T) {
+	tuple := NewTuple(1, []string{"a"}, NewSet(2))
+
+	if !tuple.Equal(NewTuple(1, []string{"a"}, NewSet(2))) {
+		t.Error("deeply equal tuples should be equal")
+	}
+	if !tuple.Project(2, 0).Equal(NewTuple(NewSet(2), 1)) {
+		t.Errorf("unexpected projection %v", tuple.Project(2, 0))
+	}
+}
+
+func Test_TupleJSON(t *testing.T) {
+	b, err := json.Marshal(NewTuple(1, "a", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[1,"a",true]` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+
+	var decoded Tuple
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Len() != 3 || decoded.At(2) != true {
+		t.Errorf("unexpected decoded tuple %v", decoded)
+	}
+}
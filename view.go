@@ -0,0 +1,547 @@
+package mapset
+
+import (
+	"sort"
+	"strings"
+)
+
+// View is a lazily evaluated set expression. Instead of materializing a new set for every operation,
+// a view only evaluates Contains and the iteration of its elements on demand against its underlying sets.
+// Modifications of the underlying sets are reflected by the view.
+// An evaluation read-locks all underlying sets once, so the sets must not be modified during Each.
+type View interface {
+	// Contains determines whether the given items are all in the view.
+	Contains(i ...interface{}) bool
+
+	// Cardinality determines the number of elements in the view by evaluating it.
+	Cardinality() int
+
+	// Each iterates over elements and executes the passed func against each element.
+	// If passed func returns true, stop iteration eagerly.
+	Each(func(interface{}) bool)
+
+	// Iterator that you can use to range over the view.
+	Iterator() *Iterator
+
+	// Materialize evaluates the view to a new set. The set uses the same implementation and options
+	// as the first underlying set.
+	Materialize() Set
+
+	// String provides the expression of the view.
+	String() string
+
+	// leaves collects the views of the underlying sets.
+	leaves(collect func(*setView))
+
+	// containsHash determines whether the element with the given hash is in the view.
+	containsHash(cores viewCores, h uint64) bool
+
+	// eachHash iterates over the elements of the view with their hashes. It returns true if cb stopped the iteration.
+	eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool
+
+	// size estimates the maximum number of elements in the view.
+	size(cores viewCores) int
+
+	// cost estimates the number of lookups that containsHash needs for an element.
+	cost() int
+
+	// template provides the view of the first underlying set.
+	template() *setView
+}
+
+// viewCores provides the elements of the underlying sets of a view while they are read-locked.
+type viewCores map[*setView]*threadUnsafeSet
+
+// ViewOf provides a view of the given set.
+func ViewOf(set Set) View {
+	return &setView{set: set}
+}
+
+// UnionView provides a view of all elements in the given views.
+func UnionView(views ...View) View {
+	if len(views) == 0 {
+		return ViewOf(NewSet())
+	}
+	return &unionView{operands: views}
+}
+
+// IntersectView provides a view of the elements that exist in all given views.
+func IntersectView(views ...View) View {
+	if len(views) == 0 {
+		return ViewOf(NewSet())
+	}
+	return &intersectView{operands: views}
+}
+
+// DifferenceView provides a view of the elements of the given base view that exist in none of the other views.
+func DifferenceView(base View, others ...View) View {
+	return &differenceView{base: base, others: others}
+}
+
+// SymmetricDifferenceView provides a view of the elements that exist in either of the given views but not in both.
+func SymmetricDifferenceView(a, b View) View {
+	return &symmetricDifferenceView{a: a, b: b}
+}
+
+// lockView read-locks the underlying sets of the given view once each and provides their elements.
+func lockView(view View) (cores viewCores, unlock func()) {
+	var leaves []*setView
+	var sets []Set
+	view.leaves(func(leaf *setView) {
+		leaves = append(leaves, leaf)
+		sets = append(sets, leaf.set)
+	})
+	locked, unlock := readLockAll(sets)
+	cores = make(viewCores, len(leaves))
+	for n, leaf := range leaves {
+		cores[leaf] = locked[n]
+	}
+	return cores, unlock
+}
+
+// viewContains determines whether all given items are in the view.
+// The items are hashed with the options of the first underlying set.
+func viewContains(view View, i []interface{}) bool {
+	cores, unlock := lockView(view)
+	defer unlock()
+	hasher := cores[view.template()].derived(0)
+	for _, val := range i {
+		if !view.containsHash(cores, hasher.hashFor(val)) {
+			return false
+		}
+	}
+	return true
+}
+
+// viewCardinality counts the elements of the view.
+func viewCardinality(view View) (count int) {
+	cores, unlock := lockView(view)
+	defer unlock()
+	view.eachHash(cores, func(interface{}, uint64) bool {
+		count++
+		return false
+	})
+	return
+}
+
+// viewEach iterates over the elements of the view.
+func viewEach(view View, cb func(interface{}) bool) {
+	cores, unlock := lockView(view)
+	defer unlock()
+	view.eachHash(cores, func(elem interface{}, _ uint64) bool {
+		return cb(elem)
+	})
+}
+
+// viewIterator iterates over the elements of the view in the background.
+func viewIterator(view View) *Iterator {
+	iterator, ch, stopCh := newIterator()
+
+	go func() {
+		defer close(ch)
+		viewEach(view, func(elem interface{}) bool {
+			select {
+			case <-stopCh:
+				return true
+			case ch <- elem:
+				return false
+			}
+		})
+	}()
+
+	return iterator
+}
+
+// materialize evaluates the view to a new set.
+func materialize(view View) Set {
+	cores, unlock := lockView(view)
+	defer unlock()
+	first := view.template()
+	result := cores[first].derived(view.size(cores))
+	view.eachHash(cores, func(elem interface{}, h uint64) bool {
+		result.addWithHash(elem, h)
+		return false
+	})
+	return resultLike(first.set, result)
+}
+
+// byCost orders the given views by their cost to evaluate containsHash, cheapest first.
+// Views of equal cost are ordered by their size, ascending if smallestFirst is set and descending otherwise.
+func byCost(views []View, cores viewCores, smallestFirst bool) []View {
+	ordered := append([]View(nil), views...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ci, cj := ordered[i].cost(), ordered[j].cost(); ci != cj {
+			return ci < cj
+		}
+		if smallestFirst {
+			return ordered[i].size(cores) < ordered[j].size(cores)
+		}
+		return ordered[i].size(cores) > ordered[j].size(cores)
+	})
+	return ordered
+}
+
+// anyContainsHash determines whether any of the given views contains the element with the given hash.
+func anyContainsHash(views []View, cores viewCores, h uint64) bool {
+	for _, view := range views {
+		if view.containsHash(cores, h) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinViews(views []View, op string) string {
+	items := make([]string, 0, len(views))
+	for _, view := range views {
+		items = append(items, view.String())
+	}
+	return "(" + strings.Join(items, " "+op+" ") + ")"
+}
+
+type setView struct {
+	set Set
+}
+
+func (v *setView) Contains(i ...interface{}) bool {
+	return v.set.Contains(i...)
+}
+
+func (v *setView) Cardinality() int {
+	return v.set.Cardinality()
+}
+
+func (v *setView) Each(cb func(interface{}) bool) {
+	v.set.Each(cb)
+}
+
+func (v *setView) Iterator() *Iterator {
+	return v.set.Iterator()
+}
+
+func (v *setView) Materialize() Set {
+	return v.set.Clone()
+}
+
+func (v *setView) String() string {
+	return v.set.String()
+}
+
+func (v *setView) leaves(collect func(*setView)) {
+	collect(v)
+}
+
+func (v *setView) containsHash(cores viewCores, h uint64) bool {
+	return cores[v].containsHash(h)
+}
+
+func (v *setView) eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool {
+	for h, elem := range cores[v].anyMap {
+		if cb(elem, h) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *setView) size(cores viewCores) int {
+	return cores[v].Cardinality()
+}
+
+func (v *setView) cost() int {
+	return 1
+}
+
+func (v *setView) template() *setView {
+	return v
+}
+
+type unionView struct {
+	operands []View
+}
+
+func (v *unionView) Contains(i ...interface{}) bool {
+	return viewContains(v, i)
+}
+
+func (v *unionView) Cardinality() int {
+	return viewCardinality(v)
+}
+
+func (v *unionView) Each(cb func(interface{}) bool) {
+	viewEach(v, cb)
+}
+
+func (v *unionView) Iterator() *Iterator {
+	return viewIterator(v)
+}
+
+func (v *unionView) Materialize() Set {
+	return materialize(v)
+}
+
+func (v *unionView) String() string {
+	return joinViews(v.operands, "|")
+}
+
+func (v *unionView) leaves(collect func(*setView)) {
+	for _, operand := range v.operands {
+		operand.leaves(collect)
+	}
+}
+
+func (v *unionView) containsHash(cores viewCores, h uint64) bool {
+	// check the cheapest and largest operands first since they are the most likely to contain the element
+	return anyContainsHash(byCost(v.operands, cores, false), cores, h)
+}
+
+func (v *unionView) eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool {
+	for n, operand := range v.operands {
+		seen := v.operands[:n]
+		stopped := operand.eachHash(cores, func(elem interface{}, h uint64) bool {
+			for _, previous := range seen {
+				if previous.containsHash(cores, h) {
+					return false
+				}
+			}
+			return cb(elem, h)
+		})
+		if stopped {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *unionView) size(cores viewCores) (size int) {
+	for _, operand := range v.operands {
+		size += operand.size(cores)
+	}
+	return
+}
+
+func (v *unionView) cost() (cost int) {
+	for _, operand := range v.operands {
+		cost += operand.cost()
+	}
+	return
+}
+
+func (v *unionView) template() *setView {
+	return v.operands[0].template()
+}
+
+type intersectView struct {
+	operands []View
+}
+
+func (v *intersectView) Contains(i ...interface{}) bool {
+	return viewContains(v, i)
+}
+
+func (v *intersectView) Cardinality() int {
+	return viewCardinality(v)
+}
+
+func (v *intersectView) Each(cb func(interface{}) bool) {
+	viewEach(v, cb)
+}
+
+func (v *intersectView) Iterator() *Iterator {
+	return viewIterator(v)
+}
+
+func (v *intersectView) Materialize() Set {
+	return materialize(v)
+}
+
+func (v *intersectView) String() string {
+	return joinViews(v.operands, "&")
+}
+
+func (v *intersectView) leaves(collect func(*setView)) {
+	for _, operand := range v.operands {
+		operand.leaves(collect)
+	}
+}
+
+func (v *intersectView) containsHash(cores viewCores, h uint64) bool {
+	// check the cheapest and smallest operands first since they are the most likely to reject the element
+	for _, operand := range byCost(v.operands, cores, true) {
+		if !operand.containsHash(cores, h) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *intersectView) eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool {
+	smallest := 0
+	for n, operand := range v.operands {
+		if operand.size(cores) < v.operands[smallest].size(cores) {
+			smallest = n
+		}
+	}
+	others := make([]View, 0, len(v.operands)-1)
+	others = append(others, v.operands[:smallest]...)
+	others = append(others, v.operands[smallest+1:]...)
+	others = byCost(others, cores, true)
+
+	return v.operands[smallest].eachHash(cores, func(elem interface{}, h uint64) bool {
+		for _, other := range others {
+			if !other.containsHash(cores, h) {
+				return false
+			}
+		}
+		return cb(elem, h)
+	})
+}
+
+func (v *intersectView) size(cores viewCores) int {
+	size := v.operands[0].size(cores)
+	for _, operand := range v.operands[1:] {
+		if s := operand.size(cores); s < size {
+			size = s
+		}
+	}
+	return size
+}
+
+func (v *intersectView) cost() (cost int) {
+	for _, operand := range v.operands {
+		cost += operand.cost()
+	}
+	return
+}
+
+func (v *intersectView) template() *setView {
+	return v.operands[0].template()
+}
+
+type differenceView struct {
+	base   View
+	others []View
+}
+
+func (v *differenceView) Contains(i ...interface{}) bool {
+	return viewContains(v, i)
+}
+
+func (v *differenceView) Cardinality() int {
+	return viewCardinality(v)
+}
+
+func (v *differenceView) Each(cb func(interface{}) bool) {
+	viewEach(v, cb)
+}
+
+func (v *differenceView) Iterator() *Iterator {
+	return viewIterator(v)
+}
+
+func (v *differenceView) Materialize() Set {
+	return materialize(v)
+}
+
+func (v *differenceView) String() string {
+	return joinViews(append([]View{v.base}, v.others...), "-")
+}
+
+func (v *differenceView) leaves(collect func(*setView)) {
+	v.base.leaves(collect)
+	for _, other := range v.others {
+		other.leaves(collect)
+	}
+}
+
+func (v *differenceView) containsHash(cores viewCores, h uint64) bool {
+	if !v.base.containsHash(cores, h) {
+		return false
+	}
+	return !anyContainsHash(byCost(v.others, cores, false), cores, h)
+}
+
+func (v *differenceView) eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool {
+	ordered := byCost(v.others, cores, false)
+	return v.base.eachHash(cores, func(elem interface{}, h uint64) bool {
+		if anyContainsHash(ordered, cores, h) {
+			return false
+		}
+		return cb(elem, h)
+	})
+}
+
+func (v *differenceView) size(cores viewCores) int {
+	return v.base.size(cores)
+}
+
+func (v *differenceView) cost() int {
+	cost := v.base.cost()
+	for _, other := range v.others {
+		cost += other.cost()
+	}
+	return cost
+}
+
+func (v *differenceView) template() *setView {
+	return v.base.template()
+}
+
+type symmetricDifferenceView struct {
+	a View
+	b View
+}
+
+func (v *symmetricDifferenceView) Contains(i ...interface{}) bool {
+	return viewContains(v, i)
+}
+
+func (v *symmetricDifferenceView) Cardinality() int {
+	return viewCardinality(v)
+}
+
+func (v *symmetricDifferenceView) Each(cb func(interface{}) bool) {
+	viewEach(v, cb)
+}
+
+func (v *symmetricDifferenceView) Iterator() *Iterator {
+	return viewIterator(v)
+}
+
+func (v *symmetricDifferenceView) Materialize() Set {
+	return materialize(v)
+}
+
+func (v *symmetricDifferenceView) String() string {
+	return joinViews([]View{v.a, v.b}, "^")
+}
+
+func (v *symmetricDifferenceView) leaves(collect func(*setView)) {
+	v.a.leaves(collect)
+	v.b.leaves(collect)
+}
+
+func (v *symmetricDifferenceView) containsHash(cores viewCores, h uint64) bool {
+	return v.a.containsHash(cores, h) != v.b.containsHash(cores, h)
+}
+
+func (v *symmetricDifferenceView) eachHash(cores viewCores, cb func(elem interface{}, h uint64) bool) bool {
+	stopped := v.a.eachHash(cores, func(elem interface{}, h uint64) bool {
+		return !v.b.containsHash(cores, h) && cb(elem, h)
+	})
+	return stopped || v.b.eachHash(cores, func(elem interface{}, h uint64) bool {
+		return !v.a.containsHash(cores, h) && cb(elem, h)
+	})
+}
+
+func (v *symmetricDifferenceView) size(cores viewCores) int {
+	return v.a.size(cores) + v.b.size(cores)
+}
+
+func (v *symmetricDifferenceView) cost() int {
+	return v.a.cost() + v.b.cost()
+}
+
+func (v *symmetricDifferenceView) template() *setView {
+	return v.a.template()
+}
@@ -0,0 +1,184 @@
+package mapset
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Views(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewUnsafeSet(3, 4, 5)
+	c := NewSet(4, 6)
+
+	tests := []struct {
+		name string
+		view View
+		want Set
+	}{
+		{name: "set", view: ViewOf(a), want: a},
+		{name: "union", view: UnionView(ViewOf(a), ViewOf(b), ViewOf(c)), want: NewSet(1, 2, 3, 4, 5, 6)},
+		{name: "intersection", view: IntersectView(ViewOf(a), ViewOf(b), ViewOf(c)), want: NewSet(4)},
+		{name: "difference", view: DifferenceView(ViewOf(a), ViewOf(b), ViewOf(c)), want: NewSet(1, 2)},
+		{name: "symmetric difference", view: SymmetricDifferenceView(ViewOf(a), ViewOf(b)), want: NewSet(1, 2, 5)},
+		{
+			name: "nested",
+			view: DifferenceView(UnionView(ViewOf(a), ViewOf(c)), IntersectView(ViewOf(b), ViewOf(c))),
+			want: NewSet(1, 2, 3, 6),
+		},
+		{name: "empty union", view: UnionView(), want: NewSet()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			materialized := tt.view.Materialize()
+			assertEqual(materialized, tt.want, t)
+			if tt.view.Cardinality() != tt.want.Cardinality() {
+				t.Errorf("Cardinality() = %d, want %d", tt.view.Cardinality(), tt.want.Cardinality())
+			}
+			tt.want.Each(func(elem interface{}) bool {
+				if !tt.view.Contains(elem) {
+					t.Errorf("view %v should contain %v", tt.view, elem)
+				}
+				return false
+			})
+			for _, absent := range []interface{}{0, 7, "4"} {
+				if tt.view.Contains(absent) {
+					t.Errorf("view %v should not contain %v", tt.view, absent)
+				}
+			}
+
+			iterated := NewSet()
+			for elem := range tt.view.Iterator().C {
+				iterated.Add(elem)
+			}
+			assertEqual(iterated, tt.want, t)
+		})
+	}
+}
+
+func Test_ViewReflectsChanges(t *testing.T) {
+	a := NewSet(1, 2)
+	b := NewSet(2, 3)
+	union := UnionView(ViewOf(a), ViewOf(b))
+
+	b.Add(4)
+	if !union.Contains(4) || union.Cardinality() != 4 {
+		t.Error("view should reflect modifications of its sets")
+	}
+
+	materialized := union.Materialize()
+	b.Add(5)
+	if materialized.Contains(5) {
+		t.Error("materialized set should not reflect later modifications")
+	}
+	if _, ok := materialized.(*threadSafeSet); !ok {
+		t.Error("materialized set should be thread-safe if the first set is thread-safe")
+	}
+}
+
+func Test_ViewString(t *testing.T) {
+	view := DifferenceView(UnionView(ViewOf(NewSet(1)), ViewOf(NewSet(2))), ViewOf(NewSet()))
+	if view.String() != "((Set{1} | Set{2}) - Set{})" {
+		t.Errorf("unexpected view expression %v", view.String())
+	}
+}
+
+func Test_ViewIteratorStop(t *testing.T) {
+	a := NewSet()
+	for i := 0; i < 100; i++ {
+		a.Add(i)
+	}
+
+	it := UnionView(ViewOf(a), ViewOf(NewSet(-1))).Iterator()
+	var count int
+	for range it.C {
+		count++
+		if count == 10 {
+			it.Stop()
+		}
+	}
+	if count != 10 {
+		t.Errorf("iteration should end after stopping, got %d elements", count)
+	}
+}
+
+func Test_ViewConcurrentWriter(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	a := NewSet(1, 2, 3)
+	b := NewSet(3, 4)
+	views := []View{
+		IntersectView(UnionView(ViewOf(a), ViewOf(b)), ViewOf(a)),
+		UnionView(ViewOf(a), ViewOf(b), ViewOf(a)),
+		DifferenceView(ViewOf(a), ViewOf(b), ViewOf(a)),
+		SymmetricDifferenceView(ViewOf(a), ViewOf(a)),
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.Add(-1)
+				a.Remove(-1)
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			for _, view := range views {
+				view.Materialize()
+				view.Contains(1)
+				view.Cardinality()
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("views should not deadlock with a concurrent writer")
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func Test_ViewByCost(t *testing.T) {
+	small := ViewOf(NewSet(1))
+	large := ViewOf(NewSet(1, 2, 3))
+	union := UnionView(ViewOf(NewSet()), ViewOf(NewSet()))
+	views := []View{union, large, small}
+
+	tests := []struct {
+		name          string
+		smallestFirst bool
+		want          []View
+	}{
+		{name: "smallest first", smallestFirst: true, want: []View{small, large, union}},
+		{name: "largest first", smallestFirst: false, want: []View{large, small, union}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cores, unlock := lockView(UnionView(views...))
+			defer unlock()
+			ordered := byCost(views, cores, tt.smallestFirst)
+			for n := range tt.want {
+				if ordered[n] != tt.want[n] {
+					t.Errorf("byCost()[%d] = %v, want %v", n, ordered[n], tt.want[n])
+				}
+			}
+			if views[0] != union {
+				t.Error("byCost should not reorder the given views")
+			}
+		})
+	}
+}